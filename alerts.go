@@ -0,0 +1,65 @@
+package main
+
+import (
+	"log/slog"
+	"time"
+
+	"qckm/internal/i18n"
+	"qckm/internal/kimai"
+)
+
+// longRunningThreshold is the configured long_running_threshold, 0 if disabled.
+func longRunningThreshold() time.Duration {
+	return time.Duration(currentConfig().LongRunningThreshold) * time.Minute
+}
+
+// endOfDayAfter is the first configured end_of_day time after begin, the
+// zero time if end_of_day is not set.
+func endOfDayAfter(begin time.Time) time.Time {
+	if currentConfig().EndOfDay == "" {
+		return time.Time{}
+	}
+	clock, _ := time.Parse("15:04", currentConfig().EndOfDay)
+	end := time.Date(begin.Year(), begin.Month(), begin.Day(), clock.Hour(), clock.Minute(), 0, 0, begin.Location())
+	if !end.After(begin) {
+		end = end.AddDate(0, 0, 1)
+	}
+	return end
+}
+
+// overdue tells why the task should probably have been stopped by now, an
+// empty reason meaning it's fine.
+func overdue(task kimai.Task, now time.Time) string {
+	if task.Id <= 0 {
+		return ""
+	}
+	begin := task.Begin()
+	if threshold := longRunningThreshold(); threshold > 0 && now.Sub(begin) >= threshold {
+		return i18n.T("running for more than %s", formatDuration(threshold))
+	}
+	if end := endOfDayAfter(begin); !end.IsZero() && !now.Before(end) {
+		return i18n.T("still running after %s", end.Format("15:04"))
+	}
+	return ""
+}
+
+// CheckAlerts notifies once per task when it runs for too long or past the
+// end of the day. The icon shows ICON_ALERT meanwhile, UpdateStatus running
+// on the same minute ticker.
+func (a *App) CheckAlerts() {
+	active := a.State().Active
+	reason := overdue(active, time.Now())
+
+	a.mu.Lock()
+	alreadyAlerted := a.alerted[active.Id] == reason
+	if reason != "" && !alreadyAlerted {
+		a.alerted = map[int]string{active.Id: reason}
+	}
+	a.mu.Unlock()
+
+	if reason == "" || alreadyAlerted {
+		return
+	}
+	slog.Info("long running task", "id", active.Id, "reason", reason)
+	Notify(i18n.T("Forgotten timer?"), i18n.T("%s is %s (%s)", active.TextOutput(), reason, taskDuration(active)))
+}
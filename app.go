@@ -0,0 +1,628 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"qckm/internal/dashboard"
+	"qckm/internal/desktop"
+	"qckm/internal/favourites"
+	"qckm/internal/history"
+	"qckm/internal/hooks"
+	"qckm/internal/i18n"
+	"qckm/internal/ipc"
+	"qckm/internal/kimai"
+	"qckm/internal/offline"
+	"qckm/internal/stats"
+	"qckm/internal/suggest"
+)
+
+// State is what the tray menu displays. Refreshes replace the slices instead
+// of modifying them, so a State copy can be read without holding the lock.
+type State struct {
+	Recent     []kimai.Task
+	Active     kimai.Task
+	Projects   []kimai.Project
+	Customers  []kimai.Customer
+	Activities []kimai.Activity
+	Week       []kimai.Task
+	Offline    bool
+	// Running are all the running timesheets when the server allows
+	// several, Active being the first one.
+	Running []kimai.Task
+	// AuthFailed is set when the server rejects the credentials, see Relogin.
+	AuthFailed bool
+	// Unsupported is set when the server is older than kimai.MIN_VERSION_ID.
+	Unsupported bool
+	// Team are the running timesheets of all the users with config.Team, nil
+	// when disabled or TeamForbidden.
+	Team          []kimai.TeamTask
+	TeamForbidden bool
+	// Stale is set when part of the data failed to refresh and was kept from
+	// an earlier refresh, Updated being the last complete one.
+	Stale   bool
+	Updated time.Time
+	// Synced is the last refresh, complete or not, Failure being its first
+	// error and nil when everything was fetched.
+	Synced  time.Time
+	Failure error
+}
+
+// App owns the tray state, shared by the refresh loop, the menu click
+// handlers and the background watchers.
+type App struct {
+	// menu is set by onReady, under mu as the control socket already
+	// listens before, see Menu
+	menu *Menu
+
+	// ctx is cancelled by Shutdown, ending the requests in flight
+	ctx    context.Context
+	cancel context.CancelFunc
+	// control is the socket of ServeControl, nil if it failed to listen
+	control *ipc.Server
+
+	// refreshCh holds at most one pending refresh, see RequestRefresh
+	refreshCh chan struct{}
+	// refreshTicker triggers the automatic refreshes, see scheduleRefresh
+	refreshTicker *time.Ticker
+
+	mu         sync.Mutex
+	state      State
+	profile    string
+	client     *kimai.Client
+	queue      *offline.Queue
+	favourites *favourites.Store
+	// history is nil if the database could not be opened, see history.go
+	history *history.Store
+	// paused is the task stopped with Pause, see pause.go
+	paused kimai.Task
+	// alerted is the reason last notified by CheckAlerts, per task id
+	alerted map[int]string
+	// pomodoro is the running cycle, see pomodoro.go
+	pomodoro *pomodoro
+	// suggestion is the last task suggested by WatchSuggestions
+	suggestion *suggest.Suggestion
+	// tracked are the running tasks of the last refresh, by id, to run the
+	// hooks on the ones started or stopped since. nil before the first one.
+	tracked map[int]kimai.Task
+	// summaryChecked is the last CheckDailySummary
+	summaryChecked time.Time
+	// dashboard is nil when disabled, see ServeDashboard
+	dashboard      *dashboard.Server
+	dashboardToken string
+}
+
+// NewApp creates the app for the profile selected in config.
+func NewApp(config Config) *App {
+	a := &App{refreshCh: make(chan struct{}, 1)}
+	a.ctx, a.cancel = context.WithCancel(context.Background())
+	a.use(config)
+	return a
+}
+
+func (a *App) use(profileConfig Config) {
+	queue, err := offline.Open(profilePath(profileConfig.SelectedProfile, "queue"))
+	if err != nil {
+		slog.Warn("offline queue disabled", "err", err)
+	}
+	pinned, err := favourites.Open(profilePath(profileConfig.SelectedProfile, "favourites"))
+	if err != nil {
+		slog.Warn("favourites disabled", "err", err)
+	}
+	paused, err := loadPaused(profilePath(profileConfig.SelectedProfile, "paused"))
+	if err != nil {
+		slog.Warn("paused task lost", "err", err)
+	}
+	state, err := loadCatalogue(profilePath(profileConfig.SelectedProfile, "catalogue"))
+	if err != nil {
+		slog.Warn("saved projects and activities lost", "err", err)
+	}
+
+	a.mu.Lock()
+	previous := a.history
+	a.history = nil
+	a.mu.Unlock()
+	// bbolt locks the file, the same profile may be used again
+	if previous != nil {
+		previous.Close()
+	}
+	store, err := history.Open(profileFile(profileConfig.SelectedProfile, "history", ".db"))
+	if err != nil {
+		slog.Warn("local history disabled", "err", err)
+	} else if state, err = loadHistory(store, state); err != nil {
+		slog.Warn("local history unreadable", "err", err)
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.profile = profileConfig.SelectedProfile
+	a.client = NewClient(profileConfig)
+	a.queue = queue
+	a.favourites = pinned
+	a.history = store
+	a.paused = paused
+	a.state = state
+	a.tracked = nil
+}
+
+// profilePath is a state file of a profile, e.g. queue.json or queue-work.json,
+// kept apart as ids are per server.
+func profilePath(profile string, name string) string {
+	return profileFile(profile, name, ".json")
+}
+
+// profileFile is profilePath with another extension.
+func profileFile(profile string, name string, ext string) string {
+	if profile == DEFAULT_PROFILE {
+		return filepath.Join(ConfigDir(), name+ext)
+	}
+	return filepath.Join(ConfigDir(), name+"-"+profile+ext)
+}
+
+// SwitchProfile points the app to another Kimai instance.
+func (a *App) SwitchProfile(name string) error {
+	profileConfig, err := currentConfig().UseProfile(name)
+	if err != nil {
+		return err
+	}
+
+	slog.Info("switching profile", "profile", name)
+	a.use(profileConfig)
+	a.RequestRefresh()
+	return nil
+}
+
+func (a *App) Profile() string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	return a.profile
+}
+
+// backend returns the client and queue of the current profile.
+func (a *App) backend() (*kimai.Client, *offline.Queue) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	return a.client, a.queue
+}
+
+// Menu is the tray menu, nil until onReady built it.
+func (a *App) Menu() *Menu {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	return a.menu
+}
+
+func (a *App) State() State {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	return a.state
+}
+
+// Queued is the number of actions waiting for the server to be reachable.
+func (a *App) Queued() int {
+	_, queue := a.backend()
+	if queue == nil {
+		return 0
+	}
+	return queue.Len()
+}
+
+// Favourites are the pinned project and activity pairs of the current profile.
+func (a *App) Favourites() []favourites.Favourite {
+	a.mu.Lock()
+	pinned := a.favourites
+	a.mu.Unlock()
+
+	if pinned == nil {
+		return nil
+	}
+	return pinned.List()
+}
+
+// IsFavourite tells whether the task project and activity are pinned.
+func (a *App) IsFavourite(task kimai.Task) bool {
+	a.mu.Lock()
+	pinned := a.favourites
+	a.mu.Unlock()
+
+	return pinned != nil && pinned.Has(task.Project.Id, task.Activity.Id)
+}
+
+// ToggleFavourite pins or unpins the task project and activity.
+func (a *App) ToggleFavourite(task kimai.Task) {
+	a.mu.Lock()
+	pinned := a.favourites
+	a.mu.Unlock()
+	if pinned == nil {
+		Notify(i18n.T("Favourites unavailable"), i18n.T("The favourites file could not be loaded"))
+		return
+	}
+
+	added, err := pinned.Toggle(task.Project, task.Activity)
+	if err != nil {
+		slog.Error("saving favourites failed", "err", err)
+		Notify(i18n.T("Saving favourites failed"), err.Error())
+	}
+	slog.Info("favourite toggled", "task", task.TextOutput(), "pinned", added)
+	a.RequestRefresh()
+}
+
+// RequestRefresh schedules a refresh. Requests made while one is already
+// pending are coalesced into it.
+func (a *App) RequestRefresh() {
+	select {
+	case a.refreshCh <- struct{}{}:
+	default:
+	}
+}
+
+// Run is the refresh loop, the only place the menu gets rebuilt.
+func (a *App) Run() {
+	// started by onReady once the menu exists
+	menu := a.Menu()
+	// the local history fills the menus while the first refresh runs
+	menu.Render(a.State())
+	for range a.refreshCh {
+		a.Refresh()
+		menu.Render(a.State())
+	}
+}
+
+// Refresh replays the offline queue and fetches the state from the server.
+// Data that failed to be fetched is kept from the previous refresh.
+func (a *App) Refresh() {
+	ctx := a.ctx
+	client, queue := a.backend()
+
+	if queue != nil && queue.Len() > 0 {
+		rejected, err := queue.Replay(ctx, client)
+		for _, e := range rejected {
+			slog.Warn("queued action rejected", "err", e)
+			Notify(i18n.T("Queued action failed"), e.Error())
+		}
+		if err != nil {
+			slog.Error("saving the offline queue failed", "err", err)
+		}
+	}
+
+	state := a.State()
+	wasStale := state.Stale
+	// failed is set when some data is kept from the previous refresh,
+	// failure being the first error
+	var failed bool
+	var failure error
+	fail := func(err error) {
+		if !failed {
+			failure = err
+		}
+		failed = true
+	}
+
+	recent, err := FetchRecent(ctx, client)
+	wasOffline := state.Offline
+	state.Offline = kimai.IsNetworkError(err)
+	if state.Offline && !wasOffline {
+		Notify(i18n.T("Kimai unreachable"), err.Error())
+	} else if !state.Offline && wasOffline {
+		Notify(i18n.T("Kimai reachable again"), a.Profile())
+	}
+	wasAuthFailed := state.AuthFailed
+	state.AuthFailed = kimai.IsAuthError(err)
+	if state.AuthFailed && !wasAuthFailed {
+		Notify(i18n.T("Authentication failed"), i18n.T("The API token was rejected, use \"Log in again…\" in the menu"))
+	}
+	wasUnsupported := state.Unsupported
+	state.Unsupported = kimai.IsUnsupportedVersion(err)
+	if state.Unsupported && !wasUnsupported {
+		Notify(i18n.T("Kimai version not supported"), err.Error())
+	}
+	if err == nil {
+		state.Recent = recent
+	} else {
+		fail(err)
+		slog.Error("fetching recent tasks failed", "err", err)
+	}
+
+	running, err := client.FetchActiveTasks(ctx)
+	activeFetched := err == nil
+	switch {
+	case err == nil && len(running) > 0:
+		state.Active = running[0]
+		state.Running = running
+	case err == nil:
+		state.Active = kimai.Task{}
+		state.Running = nil
+	default:
+		// the last known active tasks are kept so they can still be stopped
+		fail(err)
+		if !kimai.IsNetworkError(err) {
+			slog.Error("fetching the active task failed", "err", err)
+		}
+	}
+
+	weekStart := stats.StartOfWeek(time.Now())
+	week, err := client.FetchTimesheets(ctx, weekStart, weekStart.AddDate(0, 0, 7))
+	weekFetched := err == nil
+	if err == nil {
+		state.Week = week
+	} else {
+		fail(err)
+		slog.Error("fetching this week timesheets failed", "err", err)
+	}
+
+	catalogueFailed := false
+	projects, err := client.FetchProjects(ctx)
+	if err == nil {
+		state.Projects = projects
+	} else {
+		catalogueFailed = true
+		fail(err)
+		slog.Error("fetching projects failed", "err", err)
+	}
+
+	// the customers group the recent menu and name the picker entries
+	customers, err := client.FetchCustomers(ctx)
+	if err == nil {
+		state.Customers = customers
+	} else {
+		catalogueFailed = true
+		fail(err)
+		slog.Error("fetching customers failed", "err", err)
+	}
+
+	activities, err := client.FetchActivities(ctx)
+	if err == nil {
+		state.Activities = activities
+	} else {
+		catalogueFailed = true
+		fail(err)
+		slog.Error("fetching activities failed", "err", err)
+	}
+
+	if currentConfig().Team {
+		team, err := client.FetchTeamActive(ctx)
+		wasForbidden := state.TeamForbidden
+		state.TeamForbidden = kimai.IsForbidden(err)
+		switch {
+		case err == nil:
+			// not nil, the menu lists nobody
+			state.Team = append([]kimai.TeamTask{}, team...)
+		case state.TeamForbidden:
+			state.Team = nil
+			if !wasForbidden {
+				slog.Warn("the token can't see the other users' timesheets", "err", err)
+				Notify(i18n.T("Team view unavailable"), i18n.T("The API token is not allowed to see the other users' timesheets"))
+			}
+		default:
+			fail(err)
+			slog.Error("fetching the team timesheets failed", "err", err)
+		}
+	} else {
+		state.Team, state.TeamForbidden = nil, false
+	}
+
+	state.Stale = failed
+	state.Synced, state.Failure = time.Now(), failure
+	if !failed {
+		state.Updated = state.Synced
+	}
+
+	a.mu.Lock()
+	// the profile may have been switched in the meantime
+	current := a.client == client
+	if current {
+		a.state = state
+	}
+	tracked := a.tracked
+	if current && activeFetched {
+		a.tracked = map[int]kimai.Task{}
+		for _, task := range state.Running {
+			a.tracked[task.Id] = task
+		}
+	}
+	profile := a.profile
+	store := a.history
+	a.mu.Unlock()
+
+	if current {
+		if tracked != nil && activeFetched {
+			runTaskHooks(tracked, state.Running)
+		}
+		if failed && !wasStale {
+			runHook(hooks.REFRESH_FAILED, map[string]string{"error": failure.Error()})
+		}
+	}
+
+	if current && !catalogueFailed {
+		if err := saveCatalogue(profilePath(profile, "catalogue"), state); err != nil {
+			slog.Warn("saving the projects and activities failed", "err", err)
+		}
+	}
+	if current && store != nil {
+		if err := saveHistory(store, state, weekFetched); err != nil {
+			slog.Warn("saving the local history failed", "err", err)
+		}
+	}
+}
+
+func (a *App) Restart(task kimai.Task) {
+	slog.Info("restarting task", "id", task.Id, "task", task.TextOutput())
+	description, ok := a.askDescription(currentConfig().PromptDescriptionRestart, task.TextOutput(), task.Description)
+	if !ok {
+		return
+	}
+
+	ctx := a.ctx
+	client, _ := a.backend()
+	restarted, err := client.RestartTask(ctx, task.Id)
+	if err != nil {
+		a.HandleActionError(offline.RESTART, task.Id, err)
+		return
+	}
+	if description != restarted.Description {
+		if err := client.SetDescription(ctx, restarted.Id, description); err != nil {
+			slog.Error("setting the description failed", "id", restarted.Id, "err", err)
+			Notify(i18n.T("Setting the description failed"), err.Error())
+		}
+	}
+	Notify(i18n.T("Task started"), task.TextOutput())
+	a.RequestRefresh()
+}
+
+func (a *App) Stop(task kimai.Task) {
+	slog.Info("stopping task", "id", task.Id, "task", task.TextOutput())
+	description, ok := a.askDescription(currentConfig().PromptDescriptionStop, task.TextOutput(), task.Description)
+	if !ok {
+		return
+	}
+
+	ctx := a.ctx
+	client, _ := a.backend()
+	if description != task.Description {
+		if err := client.SetDescription(ctx, task.Id, description); err != nil {
+			slog.Error("setting the description failed", "id", task.Id, "err", err)
+			Notify(i18n.T("Setting the description failed"), err.Error())
+		}
+	}
+	err := stopTask(ctx, client, task)
+	if err != nil {
+		a.HandleActionError(offline.STOP, task.Id, err)
+		return
+	}
+	a.StopPomodoro()
+	Notify(i18n.T("Task stopped"), fmt.Sprintf("%s (%s)", task.TextOutput(), taskDuration(task)))
+	a.RequestRefresh()
+}
+
+// StopAll stops all the running tasks, without asking for descriptions.
+func (a *App) StopAll(tasks []kimai.Task) {
+	client, _ := a.backend()
+	stopped := 0
+	for _, task := range tasks {
+		slog.Info("stopping task", "id", task.Id, "task", task.TextOutput())
+		if err := stopTask(a.ctx, client, task); err != nil {
+			a.HandleActionError(offline.STOP, task.Id, err)
+			continue
+		}
+		stopped++
+	}
+	if stopped > 0 {
+		a.StopPomodoro()
+		Notify(i18n.T("Tasks stopped"), i18n.T("%d running task(s) stopped", stopped))
+	}
+	a.RequestRefresh()
+}
+
+// Start starts a new task, opts.Description being the prompt default.
+func (a *App) Start(project kimai.Project, activity kimai.Activity, opts kimai.StartOptions) {
+	description, ok := a.askDescription(currentConfig().PromptDescription, fmt.Sprintf("[%s] %s", project.Name, activity.Name), opts.Description)
+	if !ok {
+		return
+	}
+	opts.Description = description
+
+	slog.Info("starting task", "project", project.Name, "activity", activity.Name)
+	client, _ := a.backend()
+	_, err := client.StartTask(a.ctx, project.Id, activity.Id, opts)
+	if err != nil {
+		slog.Error("starting the task failed", "err", err)
+		Notify(i18n.T("Starting the task failed"), err.Error())
+		return
+	}
+	Notify(i18n.T("Task started"), fmt.Sprintf("[%s] %s", project.Name, activity.Name))
+	a.RequestRefresh()
+}
+
+// EditDescription lets the user change the description of a running task.
+func (a *App) EditDescription(task kimai.Task) {
+	description, ok := a.askDescription(true, task.TextOutput(), task.Description)
+	if !ok || description == task.Description {
+		return
+	}
+
+	client, _ := a.backend()
+	if err := client.SetDescription(a.ctx, task.Id, description); err != nil {
+		slog.Error("setting the description failed", "id", task.Id, "err", err)
+		Notify(i18n.T("Setting the description failed"), err.Error())
+		return
+	}
+	a.RequestRefresh()
+}
+
+// OpenWeb opens the Kimai web interface, on the edit page of the task if taskId is set.
+func (a *App) OpenWeb(taskId int) {
+	client, _ := a.backend()
+	url := client.WebURL(currentConfig().WebLocale, kimai.TIMESHEETS_WEB_PAGE)
+	if taskId > 0 {
+		url = client.TimesheetURL(currentConfig().WebLocale, taskId)
+	}
+
+	if err := desktop.OpenURL(url); err != nil {
+		slog.Error("opening the browser failed", "url", url, "err", err)
+		Notify(i18n.T("Opening Kimai failed"), err.Error())
+	}
+}
+
+// askDescription prompts for a description prefilled with current, if enabled.
+// ok is false when the user cancelled, which also cancels the action.
+func (a *App) askDescription(enabled bool, label string, current string) (description string, ok bool) {
+	if !enabled {
+		return current, true
+	}
+
+	description, err := desktop.Prompt("qckm", i18n.T("Description for %s", label), current)
+	if err == desktop.ErrCancelled {
+		return current, false
+	}
+	if err != nil {
+		// no dialog available, don't block the action on it
+		slog.Warn("description prompt failed", "err", err)
+		return current, true
+	}
+	return description, true
+}
+
+// HandleActionError queues the stop/restart action when it failed because the
+// server could not be reached, so it is replayed on the next successful refresh.
+func (a *App) HandleActionError(kind string, taskId int, err error) {
+	slog.Error("task action failed", "action", kind, "id", taskId, "err", err)
+	_, queue := a.backend()
+	if queue == nil || !kimai.IsNetworkError(err) {
+		Notify(i18n.T(fmt.Sprintf("Task %s failed", kind)), err.Error())
+		return
+	}
+
+	if err := queue.Add(kind, taskId); err != nil {
+		slog.Error("queueing the action failed", "action", kind, "id", taskId, "err", err)
+		Notify(i18n.T(fmt.Sprintf("Task %s failed", kind)), err.Error())
+		return
+	}
+	slog.Info("offline, action queued", "action", kind, "id", taskId)
+	Notify(i18n.T("Kimai unreachable"), i18n.T(fmt.Sprintf("The %s will be sent once the server is back", kind)))
+
+	a.mu.Lock()
+	a.state.Offline = true
+	a.mu.Unlock()
+	// a control request may fail before the tray is ready
+	if menu := a.Menu(); menu != nil {
+		menu.UpdateStatus(a.State(), a.Queued())
+	}
+}
+
+// Notify shows a desktop notification unless they are disabled in the config.
+func Notify(title string, message string) {
+	if !currentConfig().NotificationsEnabled() {
+		return
+	}
+	if err := desktop.Notify(title, message); err != nil {
+		slog.Warn("notification failed", "err", err)
+	}
+}
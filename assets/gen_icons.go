@@ -0,0 +1,149 @@
+//go:build ignore
+
+// gen_icons derives the tray icon variants from icon.ico, run it with
+// go generate after changing the base icon.
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"log"
+	"os"
+)
+
+var (
+	green  = color.NRGBA{0x2e, 0xb8, 0x4b, 0xff}
+	orange = color.NRGBA{0xf0, 0x8c, 0x00, 0xff}
+	red    = color.NRGBA{0xd9, 0x3b, 0x3b, 0xff}
+	black  = color.NRGBA{0, 0, 0, 0xff}
+)
+
+func main() {
+	data, err := os.ReadFile("icon.ico")
+	if err != nil {
+		log.Fatal(err)
+	}
+	base := decode(data)
+
+	write("icon-running.ico", dot(base, green, false))
+	write("icon-offline.ico", dot(grey(base), red, false))
+	write("icon-alert.ico", dot(base, orange, false))
+	write("icon-mono.ico", mono(base))
+	write("icon-mono-running.ico", dot(mono(base), black, false))
+	write("icon-mono-offline.ico", dot(mono(base), black, true))
+	write("icon-mono-alert.ico", pip(dot(mono(base), black, true), black))
+}
+
+// decode reads the first image of an ICO file holding a 32 bits BMP.
+func decode(data []byte) *image.NRGBA {
+	offset := binary.LittleEndian.Uint32(data[18:22])
+	header := data[offset:]
+	width := int(binary.LittleEndian.Uint32(header[4:8]))
+	height := int(binary.LittleEndian.Uint32(header[8:12])) / 2
+	pixels := header[40:]
+
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		row := pixels[(height-1-y)*width*4:]
+		for x := 0; x < width; x++ {
+			p := row[x*4:]
+			img.SetNRGBA(x, y, color.NRGBA{p[2], p[1], p[0], p[3]})
+		}
+	}
+	return img
+}
+
+func grey(src *image.NRGBA) *image.NRGBA {
+	img := image.NewNRGBA(src.Rect)
+	for i := 0; i < len(src.Pix); i += 4 {
+		l := uint8((299*int(src.Pix[i]) + 587*int(src.Pix[i+1]) + 114*int(src.Pix[i+2])) / 1000)
+		copy(img.Pix[i:], []uint8{l, l, l, src.Pix[i+3] / 2})
+	}
+	return img
+}
+
+// mono is the black glyph macOS template icons and monochrome themes expect,
+// dark parts of the icon are kept and light ones become transparent.
+func mono(src *image.NRGBA) *image.NRGBA {
+	img := image.NewNRGBA(src.Rect)
+	for i := 0; i < len(src.Pix); i += 4 {
+		l := (299*int(src.Pix[i]) + 587*int(src.Pix[i+1]) + 114*int(src.Pix[i+2])) / 1000
+		alpha := (255 - l) * 2
+		if alpha > 255 {
+			alpha = 255
+		}
+		copy(img.Pix[i:], []uint8{0, 0, 0, uint8(alpha * int(src.Pix[i+3]) / 255)})
+	}
+	return img
+}
+
+// dot draws a status dot in the bottom right corner, with a transparent ring
+// to separate it from the icon, hollow draws only the outline.
+func dot(src *image.NRGBA, c color.NRGBA, hollow bool) *image.NRGBA {
+	img := image.NewNRGBA(src.Rect)
+	copy(img.Pix, src.Pix)
+
+	size := src.Rect.Dx()
+	radius := size / 5
+	cx, cy := size-radius-1, size-radius-1
+	for y := cy - radius - 3; y <= cy+radius+3; y++ {
+		for x := cx - radius - 3; x <= cx+radius+3; x++ {
+			d := (x-cx)*(x-cx) + (y-cy)*(y-cy)
+			switch {
+			case d <= radius*radius && (!hollow || d >= (radius-4)*(radius-4)):
+				img.SetNRGBA(x, y, c)
+			case d <= (radius+3)*(radius+3):
+				img.SetNRGBA(x, y, color.NRGBA{})
+			}
+		}
+	}
+	return img
+}
+
+// pip fills the centre of the status dot, turning a hollow one into a bullseye.
+func pip(img *image.NRGBA, c color.NRGBA) *image.NRGBA {
+	size := img.Rect.Dx()
+	radius := size / 5
+	cx, cy := size-radius-1, size-radius-1
+	inner := radius / 3
+	for y := cy - inner; y <= cy+inner; y++ {
+		for x := cx - inner; x <= cx+inner; x++ {
+			if (x-cx)*(x-cx)+(y-cy)*(y-cy) <= inner*inner {
+				img.SetNRGBA(x, y, c)
+			}
+		}
+	}
+	return img
+}
+
+// write saves img as a single 32 bits BMP ICO, like icon.ico.
+func write(path string, img *image.NRGBA) {
+	width, height := img.Rect.Dx(), img.Rect.Dy()
+	maskRow := (width + 31) / 32 * 4
+	size := 40 + width*height*4 + maskRow*height
+
+	var buf bytes.Buffer
+	le := binary.LittleEndian
+	binary.Write(&buf, le, []uint16{0, 1, 1})
+	buf.Write([]byte{uint8(width), uint8(height), 0, 0})
+	binary.Write(&buf, le, []uint16{1, 32})
+	binary.Write(&buf, le, []uint32{uint32(size), 22})
+
+	binary.Write(&buf, le, []uint32{40, uint32(width), uint32(height * 2)})
+	binary.Write(&buf, le, []uint16{1, 32})
+	binary.Write(&buf, le, []uint32{0, uint32(width * height * 4), 0, 0, 0, 0})
+	for y := height - 1; y >= 0; y-- {
+		for x := 0; x < width; x++ {
+			p := img.NRGBAAt(x, y)
+			buf.Write([]byte{p.B, p.G, p.R, p.A})
+		}
+	}
+	// the alpha channel is used, the AND mask is left empty
+	buf.Write(make([]byte, maskRow*height))
+
+	if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+		log.Fatal(err)
+	}
+}
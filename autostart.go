@@ -0,0 +1,33 @@
+package main
+
+import (
+	"path/filepath"
+
+	"qckm/internal/autostart"
+)
+
+// ToggleAutostart installs the launcher starting the tray at login, or
+// removes it if installed. enabled is the new state.
+func ToggleAutostart() (enabled bool, err error) {
+	if enabled, err = autostart.Enabled(); err != nil {
+		return false, err
+	}
+	if enabled {
+		return false, autostart.Disable()
+	}
+
+	var args []string
+	// a config found by default is found again at login
+	if configPath != "" && configPath != filepath.Join(ConfigDir(), CONFIG_FILE) {
+		path, err := filepath.Abs(configPath)
+		if err != nil {
+			return false, err
+		}
+		args = append(args, "--config", path)
+	}
+	command, err := autostart.Command(args...)
+	if err != nil {
+		return false, err
+	}
+	return true, autostart.Enable(command)
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"errors"
+	"log/slog"
+	"strings"
+	"time"
+
+	"qckm/internal/desktop"
+	"qckm/internal/i18n"
+	"qckm/internal/kimai"
+)
+
+// BACKDATE_MINUTES are the quick choices of the Stop at and Start at menus.
+var BACKDATE_MINUTES = []int{5, 15, 30}
+
+// TimeChoice is a quick choice of the Stop at and Start at menus, computed
+// when clicked as the menu is only rebuilt on refresh.
+type TimeChoice struct {
+	Label string
+	At    func(now time.Time) time.Time
+}
+
+// TimeChoices are some minutes ago and the top of the hour.
+func TimeChoices() []TimeChoice {
+	var choices []TimeChoice
+	for _, minutes := range BACKDATE_MINUTES {
+		ago := time.Duration(minutes) * time.Minute
+		choices = append(choices, TimeChoice{i18n.T("%d minutes ago", minutes), func(now time.Time) time.Time {
+			return now.Add(-ago).Truncate(time.Minute)
+		}})
+	}
+	choices = append(choices, TimeChoice{i18n.T("Top of the hour"), func(now time.Time) time.Time {
+		return time.Date(now.Year(), now.Month(), now.Day(), now.Hour(), 0, 0, 0, now.Location())
+	}})
+	return choices
+}
+
+// parseTimeOfDay reads a typed time, either "HH:MM" today or a duration ago
+// like "10m" or "1h30m". The future is refused.
+func parseTimeOfDay(text string, now time.Time) (time.Time, error) {
+	text = strings.TrimSpace(text)
+	var at time.Time
+	if clock, err := time.Parse("15:04", text); err == nil {
+		at = time.Date(now.Year(), now.Month(), now.Day(), clock.Hour(), clock.Minute(), 0, 0, now.Location())
+	} else if ago, err := time.ParseDuration(strings.TrimPrefix(text, "-")); err == nil {
+		at = now.Add(-ago).Truncate(time.Minute)
+	} else {
+		return at, errors.New(i18n.T("%q is not a time like 09:30 or a duration ago like 10m", text))
+	}
+	if at.After(now) {
+		return at, errors.New(i18n.T("%s is in the future", at.Format("15:04")))
+	}
+	return at, nil
+}
+
+// askTime prompts for a time, ok being false when cancelled or invalid.
+func askTime(text string) (at time.Time, ok bool) {
+	answer, err := desktop.Prompt("qckm", text, time.Now().Format("15:04"))
+	if err == desktop.ErrCancelled {
+		return at, false
+	}
+	if err != nil {
+		slog.Warn("time prompt failed", "err", err)
+		Notify(i18n.T("No dialog available"), err.Error())
+		return at, false
+	}
+	if at, err = parseTimeOfDay(answer, time.Now()); err != nil {
+		Notify(i18n.T("Invalid time"), err.Error())
+		return at, false
+	}
+	return at, true
+}
+
+// StopAt stops the running task at the given time, in the past.
+func (a *App) StopAt(task kimai.Task, end time.Time) {
+	if !end.After(task.Begin()) {
+		Notify(i18n.T("Invalid time"), i18n.T("%s started at %s", task.TextOutput(), task.Begin().Format("15:04")))
+		return
+	}
+
+	slog.Info("stopping task at", "id", task.Id, "task", task.TextOutput(), "end", end)
+	client, _ := a.backend()
+	if err := client.StopTaskAt(a.ctx, task.Id, end); err != nil {
+		slog.Error("stopping the task failed", "id", task.Id, "err", err)
+		Notify(i18n.T("Stopping the task failed"), err.Error())
+		return
+	}
+	a.StopPomodoro()
+	Notify(i18n.T("Task stopped"), i18n.T("%s stopped at %s", task.TextOutput(), end.Format("15:04")))
+	a.RequestRefresh()
+}
+
+// StopAtTyped asks the end time of the running task.
+func (a *App) StopAtTyped(task kimai.Task) {
+	if end, ok := askTime(i18n.T("Stop %s at (09:30, or 10m for 10 minutes ago)", task.TextOutput())); ok {
+		a.StopAt(task, end)
+	}
+}
+
+// StartAt moves the begin of the running task, typically earlier when the
+// timer was started late.
+func (a *App) StartAt(task kimai.Task, begin time.Time) {
+	slog.Info("moving the begin of task", "id", task.Id, "task", task.TextOutput(), "begin", begin)
+	client, _ := a.backend()
+	_, err := client.UpdateTask(a.ctx, task.Id, map[string]interface{}{"begin": begin.Format(kimai.DATETIME_FORMAT)})
+	if err != nil {
+		slog.Error("changing the start time failed", "id", task.Id, "err", err)
+		Notify(i18n.T("Changing the start time failed"), err.Error())
+		return
+	}
+	Notify(i18n.T("Start time changed"), i18n.T("%s started at %s", task.TextOutput(), begin.Format("15:04")))
+	a.RequestRefresh()
+}
+
+// StartAtTyped asks the begin time of the running task.
+func (a *App) StartAtTyped(task kimai.Task) {
+	if begin, ok := askTime(i18n.T("Start %s at (09:30, or 10m for 10 minutes ago)", task.TextOutput())); ok {
+		a.StartAt(task, begin)
+	}
+}
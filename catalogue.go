@@ -0,0 +1,64 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"qckm/internal/kimai"
+)
+
+// catalogue is saved after each refresh, so the picker and the Start menu
+// are filled before the first fetch and while offline.
+type catalogue struct {
+	Projects   []kimai.Project     `json:"projects"`
+	Activities []catalogueActivity `json:"activities"`
+	Customers  []kimai.Customer    `json:"customers,omitempty"`
+}
+
+// catalogueActivity keeps the project of the activity, not serialized by kimai.Activity.
+type catalogueActivity struct {
+	Id        int    `json:"id"`
+	Name      string `json:"name"`
+	ProjectId int    `json:"project,omitempty"`
+}
+
+func loadCatalogue(path string) (State, error) {
+	var state State
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return state, nil
+	}
+	if err != nil {
+		return state, err
+	}
+
+	var saved catalogue
+	if err := json.Unmarshal(data, &saved); err != nil {
+		return state, fmt.Errorf("corrupted catalogue %s: %w", path, err)
+	}
+	state.Projects = saved.Projects
+	state.Customers = saved.Customers
+	for _, activity := range saved.Activities {
+		state.Activities = append(state.Activities, kimai.Activity{Id: activity.Id, Name: activity.Name, ProjectId: activity.ProjectId})
+	}
+	return state, nil
+}
+
+func saveCatalogue(path string, state State) error {
+	saved := catalogue{Projects: state.Projects, Customers: state.Customers}
+	for _, activity := range state.Activities {
+		saved.Activities = append(saved.Activities, catalogueActivity{Id: activity.Id, Name: activity.Name, ProjectId: activity.ProjectId})
+	}
+
+	data, err := json.MarshalIndent(saved, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, data, 0600)
+}
@@ -0,0 +1,208 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"qckm/internal/kimai"
+)
+
+const USAGE = `usage: qckm [--config path] [--profile name] [--verbose] [--demo] [command] [arguments]
+
+commands:
+  tray                          run the system tray app (default)
+  login                         store the API token in the OS keyring
+  status [--json] [--format plain|json|waybar] [--watch] [--interval seconds]
+                                show the active task, every interval with --watch
+  recent [--json]               list the recent tasks
+  stop [--json] [id]            stop the active task, or the task with the given id
+  restart [--json] <id>         restart the task with the given id
+  start [--json] <project> <activity> [description]
+                                start a new task from project and activity ids
+  search [--json] [filter]      list the project and activity ids matching the filter
+  export [--json] [--format csv|json|ics] <range>
+                                print the timesheets of today, week, last-week, month,
+                                a day or first..last days (2024-05-01..2024-05-31), as CSV
+                                by default
+  ctl [--json] <method>         ask the running tray: status, stop, restart_last or refresh
+`
+
+type command func(ctx context.Context, client *kimai.Client, args []string, asJson bool) error
+
+var commands = map[string]command{
+	"recent":  recentCommand,
+	"stop":    stopCommand,
+	"restart": restartCommand,
+	"start":   startCommand,
+	"search":  searchCommand,
+	"login":   loginCommand,
+	"ctl":     ctlCommand,
+}
+
+// flagCommands declare their own flags, besides --json, on the flag set of
+// the command line and return the command running with their values.
+var flagCommands = map[string]func(flags *flag.FlagSet) command{
+	"status": statusFlags,
+	"export": exportFlags,
+}
+
+// RunCommand runs a CLI subcommand and returns the process exit code.
+func RunCommand(client *kimai.Client, args []string) int {
+	name := args[0]
+	cmd, ok := commands[name]
+	declare, hasFlags := flagCommands[name]
+	if !ok && !hasFlags {
+		if name != "help" && name != "-h" && name != "--help" {
+			fmt.Fprintf(os.Stderr, "unknown command %q\n", name)
+		}
+		fmt.Fprint(os.Stderr, USAGE)
+		return 2
+	}
+
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	asJson := flags.Bool("json", false, "print JSON instead of text")
+	if hasFlags {
+		cmd = declare(flags)
+	}
+	if name == "export" {
+	}
+	flags.Usage = func() { fmt.Fprint(os.Stderr, USAGE) }
+	if err := flags.Parse(args[1:]); err != nil {
+		return 2
+	}
+
+	// Ctrl-C cancels the request in flight instead of killing the process mid-request
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	if err := cmd(ctx, client, flags.Args(), *asJson); err != nil {
+		if ctx.Err() != nil {
+			fmt.Fprintln(os.Stderr, "interrupted")
+			return 130
+		}
+		fmt.Fprintln(os.Stderr, err)
+		if kimai.IsAuthError(err) {
+			fmt.Fprintln(os.Stderr, "the API token was rejected, run qckm login to store a new one")
+		}
+		return 1
+	}
+	return 0
+}
+
+func recentCommand(ctx context.Context, client *kimai.Client, args []string, asJson bool) error {
+	recent, err := FetchRecent(ctx, client)
+	if err != nil {
+		return err
+	}
+
+	if asJson {
+		return printJson(recent)
+	}
+
+	for _, task := range recent {
+		printTask(task, false)
+	}
+	return nil
+}
+
+func stopCommand(ctx context.Context, client *kimai.Client, args []string, asJson bool) error {
+	running, err := client.FetchActiveTasks(ctx)
+	if err != nil {
+		return err
+	}
+
+	var task kimai.Task
+	if len(args) > 0 {
+		if task.Id, err = parseId(args[0]); err != nil {
+			return err
+		}
+		// the begin is needed to round the entry
+		for _, active := range running {
+			if active.Id == task.Id {
+				task = active
+			}
+		}
+	} else if len(running) > 0 {
+		task = running[0]
+	} else {
+		return kimai.NoActiveTaskError{}
+	}
+
+	if err := stopTask(ctx, client, task); err != nil {
+		return err
+	}
+	return printResult(asJson, "stopped", task.Id)
+}
+
+func restartCommand(ctx context.Context, client *kimai.Client, args []string, asJson bool) error {
+	if len(args) != 1 {
+		return fmt.Errorf("restart expects a task id")
+	}
+	id, err := parseId(args[0])
+	if err != nil {
+		return err
+	}
+
+	task, err := client.RestartTask(ctx, id)
+	if err != nil {
+		return err
+	}
+	return printResult(asJson, "restarted", task.Id)
+}
+
+func startCommand(ctx context.Context, client *kimai.Client, args []string, asJson bool) error {
+	if len(args) < 2 {
+		return fmt.Errorf("start expects a project id and an activity id")
+	}
+	projectId, err := parseId(args[0])
+	if err != nil {
+		return err
+	}
+	activityId, err := parseId(args[1])
+	if err != nil {
+		return err
+	}
+
+	opts := kimai.StartOptions{Description: strings.Join(args[2:], " "), Billable: currentConfig().Billable}
+	task, err := client.StartTask(ctx, projectId, activityId, opts)
+	if err != nil {
+		return err
+	}
+	return printResult(asJson, "started", task.Id)
+}
+
+func parseId(arg string) (int, error) {
+	id, err := strconv.Atoi(arg)
+	if err != nil || id <= 0 {
+		return 0, fmt.Errorf("invalid id %q", arg)
+	}
+	return id, nil
+}
+
+func printTask(task kimai.Task, withDuration bool) {
+	if withDuration {
+		fmt.Printf("%d\t%s (%s)\n", task.Id, task.Label(0), taskDuration(task))
+	} else {
+		fmt.Printf("%d\t%s\n", task.Id, task.Label(0))
+	}
+}
+
+func printResult(asJson bool, action string, id int) error {
+	if asJson {
+		return printJson(map[string]interface{}{"result": action, "id": id})
+	}
+	fmt.Printf("%s task %d\n", action, id)
+	return nil
+}
+
+func printJson(v interface{}) error {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"qckm/internal/ipc"
+	"qckm/internal/kimai"
+)
+
+// Status is what the control socket answers to ipc.STATUS.
+type Status struct {
+	Profile string      `json:"profile"`
+	Active  *kimai.Task `json:"active"`
+	// Elapsed seconds of the active task
+	Elapsed int         `json:"elapsed,omitempty"`
+	Paused  *kimai.Task `json:"paused,omitempty"`
+	Offline bool        `json:"offline"`
+	Queued  int         `json:"queued"`
+}
+
+func (a *App) Status() Status {
+	state := a.State()
+	status := Status{Profile: a.Profile(), Offline: state.Offline, Queued: a.Queued()}
+	if state.Active.Id > 0 {
+		active := state.Active
+		status.Active = &active
+		status.Elapsed = int(active.Elapsed().Seconds())
+	}
+	if paused := a.Paused(); paused.Id > 0 {
+		status.Paused = &paused
+	}
+	return status
+}
+
+// ServeControl listens on the control socket, see SocketPath. The socket is
+// also the single instance guard: ipc.ErrInUse means another tray runs,
+// other failures only disable the socket.
+func (a *App) ServeControl() error {
+	path := SocketPath()
+	server, err := ipc.Listen(path, a.handleControl)
+	if errors.Is(err, ipc.ErrInUse) {
+		return err
+	}
+	if err != nil {
+		slog.Warn("control socket disabled", "err", err)
+		return nil
+	}
+	a.control = server
+	slog.Info("control socket listening", "path", path)
+	return nil
+}
+
+// activateRunning refreshes the tray already running, the closest to
+// bringing it to the front, and returns its profile.
+func activateRunning() (string, error) {
+	var status Status
+	if err := ipc.Call(SocketPath(), ipc.STATUS, nil, &status); err != nil {
+		return "", err
+	}
+	return status.Profile, ipc.Call(SocketPath(), ipc.REFRESH, nil, nil)
+}
+
+// handleControl answers the status directly, actions are started in the
+// background as they may wait for a dialog.
+func (a *App) handleControl(method string, params json.RawMessage) (interface{}, error) {
+	slog.Debug("control request", "method", method)
+	switch method {
+	case ipc.STATUS:
+		return a.Status(), nil
+	case ipc.STOP:
+		active := a.State().Active
+		if active.Id <= 0 {
+			return nil, fmt.Errorf("no active task")
+		}
+		go a.Stop(active)
+	case ipc.RESTART_LAST:
+		go a.RestartLast()
+	case ipc.REFRESH:
+		a.RequestRefresh()
+	default:
+		return nil, fmt.Errorf("unknown method %q", method)
+	}
+	return method, nil
+}
+
+// ctlCommand sends a request to the running tray.
+func ctlCommand(ctx context.Context, client *kimai.Client, args []string, asJson bool) error {
+	if len(args) != 1 {
+		return fmt.Errorf("ctl expects one of %s, %s, %s or %s", ipc.STATUS, ipc.STOP, ipc.RESTART_LAST, ipc.REFRESH)
+	}
+
+	if args[0] != ipc.STATUS {
+		if err := ipc.Call(SocketPath(), args[0], nil, nil); err != nil {
+			return err
+		}
+		if asJson {
+			return printJson(map[string]interface{}{"result": args[0]})
+		}
+		return nil
+	}
+
+	var status Status
+	if err := ipc.Call(SocketPath(), ipc.STATUS, nil, &status); err != nil {
+		return err
+	}
+	if asJson {
+		return printJson(status)
+	}
+	switch {
+	case status.Active != nil:
+		printTask(*status.Active, true)
+	case status.Paused != nil:
+		fmt.Printf("paused\t%s\n", status.Paused.Label(0))
+	default:
+		fmt.Println("no active task")
+	}
+	if status.Offline {
+		fmt.Printf("offline, %d queued action(s)\n", status.Queued)
+	}
+	return nil
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"log/slog"
+	"strconv"
+
+	"qckm/internal/desktop"
+	"qckm/internal/i18n"
+	"qckm/internal/kimai"
+)
+
+// taskSummary describes the task for standup notes or a ticket, e.g.
+// "[ACME] Development — 1:23 h, started 09:12".
+func taskSummary(task kimai.Task) string {
+	return i18n.T("%s — %s, started %s", task.TextOutput(), taskDuration(task), task.Begin().Format("15:04"))
+}
+
+// CopySummary puts the summary of the task on the clipboard.
+func (a *App) CopySummary(task kimai.Task) {
+	a.copy(taskSummary(task))
+}
+
+func (a *App) CopyId(task kimai.Task) {
+	a.copy(strconv.Itoa(task.Id))
+}
+
+// CopyLink puts the address of the task in the Kimai web interface on the clipboard.
+func (a *App) CopyLink(task kimai.Task) {
+	client, _ := a.backend()
+	a.copy(client.TimesheetURL(currentConfig().WebLocale, task.Id))
+}
+
+func (a *App) copy(text string) {
+	if err := desktop.Copy(text); err != nil {
+		slog.Error("copying to the clipboard failed", "err", err)
+		Notify(i18n.T("Copying failed"), err.Error())
+		return
+	}
+	Notify(i18n.T("Copied to the clipboard"), text)
+}
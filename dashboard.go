@@ -0,0 +1,157 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"time"
+
+	"qckm/internal/dashboard"
+	"qckm/internal/desktop"
+	"qckm/internal/i18n"
+	"qckm/internal/kimai"
+	"qckm/internal/stats"
+)
+
+// DashboardConfig serves the web dashboard on a local port.
+type DashboardConfig struct {
+	// Port on 127.0.0.1, 0 disables the dashboard.
+	Port int `yaml:"port"`
+	// Token protecting the dashboard, a random one per run if empty.
+	Token string `yaml:"token"`
+}
+
+func (c DashboardConfig) check() error {
+	if c.Port < 0 || c.Port > 65535 {
+		return fmt.Errorf("invalid dashboard port %d", c.Port)
+	}
+	return nil
+}
+
+// ServeDashboard (re)starts the dashboard with the current config, failures
+// only disabling it.
+func (a *App) ServeDashboard() {
+	config := currentConfig()
+	a.mu.Lock()
+	previous := a.dashboard
+	a.dashboard = nil
+	if a.dashboardToken == "" {
+		a.dashboardToken = dashboard.NewToken()
+	}
+	token := a.dashboardToken
+	a.mu.Unlock()
+	if previous != nil {
+		previous.Close()
+	}
+	if config.Dashboard.Port == 0 {
+		return
+	}
+
+	if config.Dashboard.Token != "" {
+		token = config.Dashboard.Token
+	}
+	server, err := dashboard.Listen(config.Dashboard.Port, token, dashboardBackend{a})
+	if err != nil {
+		slog.Warn("dashboard disabled", "err", err)
+		Notify(i18n.T("Dashboard unavailable"), err.Error())
+		return
+	}
+	slog.Info("dashboard listening", "port", config.Dashboard.Port)
+
+	a.mu.Lock()
+	a.dashboard = server
+	a.mu.Unlock()
+}
+
+// OpenDashboard opens the dashboard in the browser.
+func (a *App) OpenDashboard() {
+	a.mu.Lock()
+	server := a.dashboard
+	a.mu.Unlock()
+	if server == nil {
+		Notify(i18n.T("Dashboard unavailable"), i18n.T("Set dashboard.port in the config file"))
+		return
+	}
+	if err := desktop.OpenURL(server.URL()); err != nil {
+		slog.Error("opening the browser failed", "err", err)
+		Notify(i18n.T("Opening the dashboard failed"), err.Error())
+	}
+}
+
+// dashboardBackend shows the tray state, the actions behaving as the menu ones.
+type dashboardBackend struct {
+	app *App
+}
+
+func (b dashboardBackend) Snapshot() dashboard.Snapshot {
+	state := b.app.State()
+	now := time.Now()
+	snapshot := dashboard.Snapshot{
+		Profile:    b.app.Profile(),
+		Running:    []dashboard.Task{},
+		Recent:     []dashboard.Task{},
+		WeekTarget: int(weeklyTarget().Seconds()),
+		Offline:    state.Offline,
+		Stale:      state.Stale,
+		Updated:    state.Updated,
+	}
+	if state.Failure != nil {
+		snapshot.Failure = state.Failure.Error()
+	}
+	for _, task := range runningTasks(state) {
+		snapshot.Running = append(snapshot.Running, dashboardTask(task))
+	}
+	for _, task := range state.Recent {
+		snapshot.Recent = append(snapshot.Recent, dashboardTask(task))
+	}
+
+	week := stats.Summarize(state.Week, stats.StartOfWeek(now), now.Add(time.Second))
+	totals := map[string]time.Duration{}
+	for _, day := range week.PerDay {
+		totals[day.Day.Format("2006-01-02")] = day.Total
+	}
+	for i := 0; i < 7; i++ {
+		day := stats.StartOfWeek(now).AddDate(0, 0, i)
+		date := day.Format("2006-01-02")
+		snapshot.Week = append(snapshot.Week, dashboard.Day{Date: date, Name: day.Format("Monday"), Seconds: int(totals[date].Seconds())})
+	}
+	return snapshot
+}
+
+func (b dashboardBackend) Stop(id int) error {
+	for _, task := range runningTasks(b.app.State()) {
+		if task.Id == id {
+			go b.app.Stop(task)
+			return nil
+		}
+	}
+	return fmt.Errorf("task %d is not running", id)
+}
+
+func (b dashboardBackend) Restart(id int) error {
+	for _, task := range b.app.State().Recent {
+		if task.Id == id {
+			go b.app.Restart(task)
+			return nil
+		}
+	}
+	return fmt.Errorf("task %d is not a recent one", id)
+}
+
+// runningTasks are all the running tasks, the active one without Running.
+func runningTasks(state State) []kimai.Task {
+	if len(state.Running) > 0 {
+		return state.Running
+	}
+	if state.Active.Id > 0 {
+		return []kimai.Task{state.Active}
+	}
+	return nil
+}
+
+func dashboardTask(task kimai.Task) dashboard.Task {
+	shown := dashboard.Task{Id: task.Id, Label: task.Label(0), Begin: task.Begin()}
+	if task.Running() {
+		shown.Elapsed = int(task.Elapsed().Seconds())
+	}
+	return shown
+}
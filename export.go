@@ -0,0 +1,124 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"qckm/internal/desktop"
+	"qckm/internal/export"
+	"qckm/internal/i18n"
+	"qckm/internal/kimai"
+)
+
+// exportDir is the configured export_dir, ~/Downloads (or the home directory) by default.
+func exportDir() string {
+	config := currentConfig()
+	homeDir, _ := os.UserHomeDir()
+	if strings.HasPrefix(config.ExportDir, "~/") {
+		return filepath.Join(homeDir, config.ExportDir[2:])
+	}
+	if config.ExportDir != "" {
+		return config.ExportDir
+	}
+	if info, err := os.Stat(filepath.Join(homeDir, "Downloads")); err == nil && info.IsDir() {
+		return filepath.Join(homeDir, "Downloads")
+	}
+	return homeDir
+}
+
+// fetchExport renders the timesheets of the named range in the given format,
+// the configured meta_fields having a CSV column each.
+func fetchExport(ctx context.Context, client *kimai.Client, rangeName string, format string) ([]byte, error) {
+	from, to, err := export.Range(rangeName, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	tasks, err := client.FetchTimesheets(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	var meta []string
+	for name := range currentConfig().MetaFields {
+		meta = append(meta, name)
+	}
+	sort.Strings(meta)
+
+	var buf bytes.Buffer
+	if err := export.Write(&buf, format, tasks, meta); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Export saves the timesheets of the named range in export_dir, or copies
+// them to the clipboard, in the given format.
+func (a *App) Export(rangeName string, format string, toClipboard bool) {
+	client, _ := a.backend()
+	data, err := fetchExport(a.ctx, client, rangeName, format)
+	if err != nil {
+		slog.Error("export failed", "range", rangeName, "err", err)
+		Notify(i18n.T("Export failed"), err.Error())
+		return
+	}
+
+	if toClipboard {
+		if err := desktop.Copy(string(data)); err != nil {
+			slog.Error("copying the export failed", "err", err)
+			Notify(i18n.T("Export failed"), err.Error())
+			return
+		}
+		Notify(i18n.T("Timesheets copied"), i18n.T("%s, as %s", rangeName, format))
+		return
+	}
+
+	name := fmt.Sprintf("qckm-%s-%s.%s", rangeName, time.Now().Format("2006-01-02"), format)
+	path := filepath.Join(exportDir(), name)
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		slog.Error("creating the export directory failed", "path", path, "err", err)
+		Notify(i18n.T("Export failed"), err.Error())
+		return
+	}
+	if err := ioutil.WriteFile(path, data, 0600); err != nil {
+		slog.Error("writing the export failed", "path", path, "err", err)
+		Notify(i18n.T("Export failed"), err.Error())
+		return
+	}
+	slog.Info("timesheets exported", "range", rangeName, "path", path)
+	Notify(i18n.T("Timesheets exported"), path)
+}
+
+// exportFlags declares the --format of the export command, see flagCommands.
+func exportFlags(flags *flag.FlagSet) command {
+	format := flags.String("format", export.CSV, "output format: csv, json or ics")
+	return func(ctx context.Context, client *kimai.Client, args []string, asJson bool) error {
+		return exportCommand(ctx, client, args, *format, asJson)
+	}
+}
+
+// exportCommand prints the timesheets of a range, as CSV, with --json as
+// JSON, or in the format given.
+func exportCommand(ctx context.Context, client *kimai.Client, args []string, format string, asJson bool) error {
+	if len(args) != 1 {
+		return fmt.Errorf("export expects a range, one of %v, a day or first..last days", export.RANGES)
+	}
+	if asJson {
+		format = export.JSON
+	}
+
+	data, err := fetchExport(ctx, client, args[0], format)
+	if err != nil {
+		return err
+	}
+	_, err = os.Stdout.Write(data)
+	return err
+}
@@ -1,13 +1,21 @@
 module qckm
 
-go 1.20
+go 1.21
 
 require (
+	github.com/fsnotify/fsnotify v1.7.0
 	github.com/heb-dtc/systray v0.0.0-20230519102851-b9fb8e81c1c5
+	github.com/zalando/go-keyring v0.2.3
+	go.etcd.io/bbolt v1.3.10
+	golang.design/x/hotkey v0.4.1
+	golang.org/x/sys v0.8.0
+	golang.org/x/term v0.8.0
 	gopkg.in/yaml.v2 v2.4.0
 )
 
 require (
+	github.com/alessio/shellescape v1.4.1 // indirect
+	github.com/danieljoos/wincred v1.2.0 // indirect
 	github.com/getlantern/context v0.0.0-20190109183933-c447772a6520 // indirect
 	github.com/getlantern/errors v0.0.0-20190325191628-abdb3e3e36f7 // indirect
 	github.com/getlantern/golog v0.0.0-20190830074920-4ef2e798c2d7 // indirect
@@ -15,6 +23,6 @@ require (
 	github.com/getlantern/hidden v0.0.0-20190325191715-f02dbb02be55 // indirect
 	github.com/getlantern/ops v0.0.0-20190325191751-d70cb0d6f85f // indirect
 	github.com/go-stack/stack v1.8.0 // indirect
+	github.com/godbus/dbus/v5 v5.1.0 // indirect
 	github.com/oxtoacart/bpool v0.0.0-20190530202638-03653db5a59c // indirect
-	golang.org/x/sys v0.7.0 // indirect
 )
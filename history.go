@@ -0,0 +1,49 @@
+package main
+
+import (
+	"time"
+
+	"qckm/internal/history"
+	"qckm/internal/stats"
+)
+
+// HISTORY_WEEKS of timesheets are kept in the local history.
+const HISTORY_WEEKS = 8
+
+// loadHistory fills state with the recent, running and this week's tasks of
+// the last refresh, so the menus show them before the first fetch.
+func loadHistory(store *history.Store, state State) (State, error) {
+	snapshot, err := store.Snapshot()
+	if err != nil {
+		return state, err
+	}
+	weekStart := stats.StartOfWeek(time.Now())
+	week, err := store.Between(weekStart, weekStart.AddDate(0, 0, 7))
+	if err != nil {
+		return state, err
+	}
+
+	state.Recent = snapshot.Recent
+	state.Running = snapshot.Running
+	if len(snapshot.Running) > 0 {
+		state.Active = snapshot.Running[0]
+	}
+	state.Week = week
+	state.Updated = snapshot.Updated
+	return state, nil
+}
+
+// saveHistory stores the week fetched by a refresh, if it did not fail, and
+// what the menus show.
+func saveHistory(store *history.Store, state State, weekFetched bool) error {
+	if weekFetched {
+		weekStart := stats.StartOfWeek(time.Now())
+		if err := store.Sync(weekStart, weekStart.AddDate(0, 0, 7), state.Week); err != nil {
+			return err
+		}
+		if err := store.Prune(weekStart.AddDate(0, 0, -7*HISTORY_WEEKS)); err != nil {
+			return err
+		}
+	}
+	return store.SaveSnapshot(history.Snapshot{Recent: state.Recent, Running: state.Running, Updated: state.Updated})
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strconv"
+	"strings"
+
+	"qckm/internal/hooks"
+	"qckm/internal/i18n"
+	"qckm/internal/kimai"
+)
+
+// CustomAction is a menu entry running a command, see hooks.Run.
+type CustomAction struct {
+	Title   string `yaml:"title"`
+	Command string `yaml:"command"`
+}
+
+func checkHooks(events map[string]string, actions []CustomAction) error {
+	for event := range events {
+		if !slices.Contains(hooks.EVENTS, event) {
+			return fmt.Errorf("invalid hook %q, expected one of %v", event, hooks.EVENTS)
+		}
+	}
+	for i, action := range actions {
+		if strings.TrimSpace(action.Title) == "" || strings.TrimSpace(action.Command) == "" {
+			return fmt.Errorf("custom action %d needs a title and a command", i+1)
+		}
+	}
+	return nil
+}
+
+// taskEnv describes the task to the commands, e.g. QCKM_PROJECT, the custom
+// fields as QCKM_META_ and their name, see hooks.EnvName.
+func taskEnv(task kimai.Task) map[string]string {
+	if task.Id <= 0 {
+		return map[string]string{}
+	}
+	env := map[string]string{
+		"task_id":     strconv.Itoa(task.Id),
+		"project":     task.Project.Name,
+		"project_id":  strconv.Itoa(task.Project.Id),
+		"activity":    task.Activity.Name,
+		"activity_id": strconv.Itoa(task.Activity.Id),
+		"description": task.Description,
+		"tags":        strings.Join(task.Tags, ","),
+		"begin":       task.Begin().Format(kimai.DATETIME_FORMAT),
+		"duration":    taskDuration(task),
+	}
+	for _, field := range task.MetaFields {
+		env["meta_"+field.Name] = field.Value
+	}
+	return env
+}
+
+// runTaskHooks runs the started and stopped hooks for the tasks that
+// appeared in or disappeared from the running ones, whoever started or
+// stopped them.
+func runTaskHooks(previous map[int]kimai.Task, running []kimai.Task) {
+	for _, task := range running {
+		if _, ok := previous[task.Id]; !ok {
+			runHook(hooks.STARTED, taskEnv(task))
+		}
+	}
+	for id, task := range previous {
+		if !slices.ContainsFunc(running, func(t kimai.Task) bool { return t.Id == id }) {
+			runHook(hooks.STOPPED, taskEnv(task))
+		}
+	}
+}
+
+// runHook runs the command of the event in the background, if configured.
+func runHook(event string, env map[string]string) {
+	command := currentConfig().Hooks[event]
+	if command == "" {
+		return
+	}
+	env["event"] = event
+	go func() {
+		out, err := hooks.Run(context.Background(), command, env)
+		if err != nil {
+			slog.Warn("hook failed", "event", event, "err", err, "output", strings.TrimSpace(string(out)))
+			return
+		}
+		slog.Debug("hook ran", "event", event, "output", strings.TrimSpace(string(out)))
+	}()
+}
+
+// RunAction runs a custom action with the active task in the environment.
+func (a *App) RunAction(action CustomAction) {
+	env := taskEnv(a.State().Active)
+	env["action"] = action.Title
+	out, err := hooks.Run(a.ctx, action.Command, env)
+	if err != nil {
+		slog.Error("custom action failed", "action", action.Title, "err", err, "output", strings.TrimSpace(string(out)))
+		Notify(i18n.T("%s failed", action.Title), strings.TrimSpace(err.Error()+"\n"+string(out)))
+		return
+	}
+	slog.Info("custom action ran", "action", action.Title)
+	a.RequestRefresh()
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"sort"
+
+	"qckm/internal/hotkeys"
+	"qckm/internal/i18n"
+)
+
+// Actions that can be bound to a global shortcut in the hotkeys config map.
+const (
+	HOTKEY_STOP         = "stop"
+	HOTKEY_RESTART_LAST = "restart_last"
+	HOTKEY_TOGGLE       = "toggle"
+	HOTKEY_PAUSE        = "pause"
+	HOTKEY_RESUME       = "resume"
+)
+
+var HOTKEY_ACTIONS = []string{HOTKEY_STOP, HOTKEY_RESTART_LAST, HOTKEY_TOGGLE, HOTKEY_PAUSE, HOTKEY_RESUME}
+
+// checkHotkeys validates the action names and shortcuts of the hotkeys config.
+func checkHotkeys(bindings map[string]string) error {
+	for action, spec := range bindings {
+		known := false
+		for _, name := range HOTKEY_ACTIONS {
+			known = known || name == action
+		}
+		if !known {
+			return fmt.Errorf("invalid hotkeys action %q, expected one of %v", action, HOTKEY_ACTIONS)
+		}
+		// an unsupported build is reported when registering, not as a config error
+		if _, _, err := hotkeys.Parse(spec); err != nil && err != hotkeys.ErrUnsupported {
+			return err
+		}
+	}
+	return nil
+}
+
+// RegisterHotkeys grabs the configured global shortcuts. Failures are logged
+// and notified but don't prevent the tray from running.
+func (a *App) RegisterHotkeys() {
+	var actions []string
+	for action := range currentConfig().Hotkeys {
+		actions = append(actions, action)
+	}
+	sort.Strings(actions)
+
+	for _, action := range actions {
+		spec := currentConfig().Hotkeys[action]
+		run := a.hotkeyAction(action)
+		if _, err := hotkeys.Register(spec, run); err != nil {
+			slog.Error("registering hotkey failed", "action", action, "hotkey", spec, "err", err)
+			Notify(i18n.T("Hotkey unavailable"), err.Error())
+			if err == hotkeys.ErrUnsupported {
+				return
+			}
+			continue
+		}
+		slog.Info("hotkey registered", "action", action, "hotkey", spec)
+	}
+}
+
+func (a *App) hotkeyAction(action string) func() {
+	return func() {
+		slog.Debug("hotkey pressed", "action", action)
+		state := a.State()
+		switch action {
+		case HOTKEY_STOP:
+			if state.Active.Id > 0 {
+				a.Stop(state.Active)
+			}
+		case HOTKEY_RESTART_LAST:
+			a.RestartLast()
+		case HOTKEY_TOGGLE:
+			switch {
+			case state.Active.Id > 0:
+				a.Stop(state.Active)
+			case a.Paused().Id > 0:
+				a.Resume()
+			default:
+				a.RestartLast()
+			}
+		case HOTKEY_PAUSE:
+			if state.Active.Id > 0 {
+				a.Pause(state.Active)
+			}
+		case HOTKEY_RESUME:
+			a.Resume()
+		}
+	}
+}
+
+// RestartLast restarts the most recent task that is not the running one.
+func (a *App) RestartLast() {
+	state := a.State()
+	for _, task := range state.Recent {
+		if task.Id != state.Active.Id {
+			a.Restart(task)
+			return
+		}
+	}
+	Notify(i18n.T("Nothing to restart"), i18n.T("There is no recent task"))
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	_ "embed"
+	"runtime"
+	"time"
+
+	"github.com/heb-dtc/systray"
+)
+
+//go:generate sh -c "cd assets && go run gen_icons.go"
+
+var (
+	//go:embed "assets/icon.ico"
+	icon []byte
+	//go:embed "assets/icon-running.ico"
+	iconRunning []byte
+	//go:embed "assets/icon-offline.ico"
+	iconOffline []byte
+	//go:embed "assets/icon-alert.ico"
+	iconAlert []byte
+	//go:embed "assets/icon-mono.ico"
+	iconMono []byte
+	//go:embed "assets/icon-mono-running.ico"
+	iconMonoRunning []byte
+	//go:embed "assets/icon-mono-offline.ico"
+	iconMonoOffline []byte
+	//go:embed "assets/icon-mono-alert.ico"
+	iconMonoAlert []byte
+)
+
+const (
+	ICON_STYLE_COLOR = "color"
+	ICON_STYLE_MONO  = "mono"
+
+	ICON_IDLE    = "idle"
+	ICON_RUNNING = "running"
+	ICON_OFFLINE = "offline"
+	ICON_ALERT   = "alert"
+)
+
+var icons = map[string]map[string][]byte{
+	ICON_STYLE_COLOR: {ICON_IDLE: icon, ICON_RUNNING: iconRunning, ICON_OFFLINE: iconOffline, ICON_ALERT: iconAlert},
+	ICON_STYLE_MONO:  {ICON_IDLE: iconMono, ICON_RUNNING: iconMonoRunning, ICON_OFFLINE: iconMonoOffline, ICON_ALERT: iconMonoAlert},
+}
+
+// iconState is the icon variant matching the state, errors and offline first.
+func iconState(state State) string {
+	switch {
+	case state.Offline || state.AuthFailed:
+		return ICON_OFFLINE
+	case overdue(state.Active, time.Now()) != "":
+		return ICON_ALERT
+	case state.Active.Id > 0:
+		return ICON_RUNNING
+	default:
+		return ICON_IDLE
+	}
+}
+
+// setIcon shows the variant in the configured icon_style. On macOS the mono
+// icons are template images, recolored by the system for dark mode. It is a
+// variable for the tests, which run without a tray.
+var setIcon = func(variant string) {
+	if currentConfig().IconStyle == ICON_STYLE_MONO && runtime.GOOS == "darwin" {
+		systray.SetTemplateIcon(icons[ICON_STYLE_MONO][variant], icons[ICON_STYLE_COLOR][variant])
+		return
+	}
+	systray.SetIcon(icons[currentConfig().IconStyle][variant])
+}
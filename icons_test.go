@@ -0,0 +1,47 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"qckm/internal/kimai"
+)
+
+func TestUpdateIcon(t *testing.T) {
+	setConfig(Config{LongRunningThreshold: 60})
+	var shown []string
+	defer func(previous func(string)) { setIcon = previous }(setIcon)
+	setIcon = func(variant string) { shown = append(shown, variant) }
+
+	running := kimai.Task{Id: 1, StartTime: time.Now().Add(-time.Minute).Format(time.RFC3339)}
+	overdue := kimai.Task{Id: 2, StartTime: time.Now().Add(-2 * time.Hour).Format(time.RFC3339)}
+	steps := []struct {
+		state State
+		want  string
+	}{
+		{State{}, ICON_IDLE},
+		{State{Active: running}, ICON_RUNNING},
+		{State{Active: running}, ICON_RUNNING},
+		{State{Active: running, Offline: true}, ICON_OFFLINE},
+		{State{Active: overdue}, ICON_ALERT},
+		{State{}, ICON_IDLE},
+	}
+
+	m := &Menu{icon: ICON_IDLE}
+	for i, step := range steps {
+		m.updateIcon(step.state)
+		if m.icon != step.want {
+			t.Errorf("step %d: icon %q, want %q", i, m.icon, step.want)
+		}
+	}
+	want := []string{ICON_RUNNING, ICON_OFFLINE, ICON_ALERT, ICON_IDLE}
+	if len(shown) != len(want) {
+		t.Fatalf("icons shown %v, want %v", shown, want)
+	}
+	for i := range want {
+		if shown[i] != want[i] {
+			t.Errorf("icons shown %v, want %v", shown, want)
+			break
+		}
+	}
+}
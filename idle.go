@@ -0,0 +1,99 @@
+package main
+
+import (
+	"log/slog"
+	"time"
+
+	"qckm/internal/desktop"
+	"qckm/internal/i18n"
+	"qckm/internal/idle"
+	"qckm/internal/kimai"
+)
+
+const (
+	IDLE_ACTION_PROMPT = "prompt"
+	IDLE_ACTION_STOP   = "stop"
+	IDLE_POLL_INTERVAL = 30 * time.Second
+)
+
+// WatchIdle polls the user idle time, and once it went over the configured
+// threshold either stops the active task at the moment the user left, or asks
+// on return whether the idle time should be kept.
+func (a *App) WatchIdle() {
+	threshold := time.Duration(currentConfig().IdleThreshold) * time.Minute
+	var idleSince time.Time
+
+	ticker := time.NewTicker(IDLE_POLL_INTERVAL)
+	for range ticker.C {
+		idleFor, err := idle.Duration()
+		if err != nil {
+			slog.Warn("idle detection disabled", "err", err)
+			return
+		}
+
+		active := a.State().Active
+		if idleFor >= threshold {
+			if !idleSince.IsZero() || active.Id <= 0 {
+				continue
+			}
+			idleSince = time.Now().Add(-idleFor)
+			// a task started while idle, e.g. by a hotkey or remotely, ends no
+			// earlier than it began
+			if begin := active.Begin(); begin.After(idleSince) {
+				idleSince = begin
+			}
+			if currentConfig().IdleAction == IDLE_ACTION_STOP {
+				a.StopIdleTask(active, idleSince)
+			}
+			continue
+		}
+
+		if !idleSince.IsZero() {
+			since := idleSince
+			idleSince = time.Time{}
+			if currentConfig().IdleAction == IDLE_ACTION_PROMPT && active.Id > 0 {
+				a.PromptIdleTime(active, since)
+			}
+		}
+	}
+}
+
+func (a *App) StopIdleTask(task kimai.Task, idleSince time.Time) {
+	client, _ := a.backend()
+	err := client.StopTaskAt(a.ctx, task.Id, idleSince)
+	if err != nil {
+		slog.Error("stopping the idle task failed", "id", task.Id, "err", err)
+		Notify(i18n.T("Stopping the idle task failed"), err.Error())
+		return
+	}
+	Notify(i18n.T("Task stopped while idle"), i18n.T("%s stopped at %s", task.TextOutput(), idleSince.Format("15:04")))
+	a.RequestRefresh()
+}
+
+// PromptIdleTime asks whether to keep the idle time, if not the task is
+// stopped at the moment the user left and restarted from now on.
+func (a *App) PromptIdleTime(task kimai.Task, idleSince time.Time) {
+	text := i18n.T("You have been idle since %s while tracking %s.\nKeep the idle time?",
+		idleSince.Format("15:04"), task.TextOutput())
+	keep, err := desktop.Confirm("qckm", text)
+	if err != nil {
+		slog.Warn("idle prompt failed", "err", err)
+		return
+	}
+	if keep {
+		return
+	}
+
+	ctx := a.ctx
+	client, _ := a.backend()
+	if err := client.StopTaskAt(ctx, task.Id, idleSince); err != nil {
+		slog.Error("discarding the idle time failed", "id", task.Id, "err", err)
+		Notify(i18n.T("Discarding the idle time failed"), err.Error())
+		return
+	}
+	if _, err := client.RestartTask(ctx, task.Id); err != nil {
+		slog.Error("restarting the task after idle failed", "id", task.Id, "err", err)
+		Notify(i18n.T("Restarting the task failed"), err.Error())
+	}
+	a.RequestRefresh()
+}
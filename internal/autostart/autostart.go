@@ -0,0 +1,23 @@
+// Package autostart installs the launcher starting qckm at login: an XDG
+// autostart entry, a macOS LaunchAgent or a Windows Run registry value.
+package autostart
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// NAME identifies the launcher of qckm among the others.
+const NAME = "qckm"
+
+// Command is the current executable with args, the one to start at login.
+func Command(args ...string) ([]string, error) {
+	exe, err := os.Executable()
+	if err != nil {
+		return nil, err
+	}
+	if resolved, err := filepath.EvalSymlinks(exe); err == nil {
+		exe = resolved
+	}
+	return append([]string{exe}, args...), nil
+}
@@ -0,0 +1,36 @@
+package autostart
+
+import (
+	"errors"
+	"os/exec"
+	"strings"
+)
+
+// RUN_KEY holds the programs started at login of the current user.
+const RUN_KEY = `HKCU\Software\Microsoft\Windows\CurrentVersion\Run`
+
+func Enabled() (bool, error) {
+	err := exec.Command("reg", "query", RUN_KEY, "/v", NAME).Run()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		// reg exits with 1 when the value does not exist
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func Enable(command []string) error {
+	quoted := make([]string, len(command))
+	for i, arg := range command {
+		quoted[i] = `"` + arg + `"`
+	}
+	return exec.Command("reg", "add", RUN_KEY, "/v", NAME, "/t", "REG_SZ", "/d", strings.Join(quoted, " "), "/f").Run()
+}
+
+func Disable() error {
+	enabled, err := Enabled()
+	if err != nil || !enabled {
+		return err
+	}
+	return exec.Command("reg", "delete", RUN_KEY, "/v", NAME, "/f").Run()
+}
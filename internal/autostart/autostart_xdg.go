@@ -0,0 +1,75 @@
+//go:build !darwin && !windows
+
+package autostart
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// path is the XDG autostart entry, in $XDG_CONFIG_HOME/autostart.
+func path() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "autostart", NAME+".desktop"), nil
+}
+
+func Enabled() (bool, error) {
+	file, err := path()
+	if err != nil {
+		return false, err
+	}
+	_, err = os.Stat(file)
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func Enable(command []string) error {
+	file, err := path()
+	if err != nil {
+		return err
+	}
+
+	quoted := make([]string, len(command))
+	for i, arg := range command {
+		quoted[i] = desktopEntryQuote(arg)
+	}
+	entry := "[Desktop Entry]\n" +
+		"Type=Application\n" +
+		"Name=qckm\n" +
+		"Comment=Kimai time tracking in the system tray\n" +
+		"Exec=" + strings.Join(quoted, " ") + "\n" +
+		"Terminal=false\n" +
+		"X-GNOME-Autostart-enabled=true\n"
+
+	if err := os.MkdirAll(filepath.Dir(file), 0700); err != nil {
+		return err
+	}
+	return os.WriteFile(file, []byte(entry), 0644)
+}
+
+func Disable() error {
+	file, err := path()
+	if err != nil {
+		return err
+	}
+	err = os.Remove(file)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
+
+// desktopEntryQuote quotes an Exec argument as the desktop entry spec asks.
+func desktopEntryQuote(arg string) string {
+	if !strings.ContainsAny(arg, " \t\n\"'\\><~|&;$*?#()`%") {
+		return arg
+	}
+	r := strings.NewReplacer(`\`, `\\\\`, `"`, `\\"`, "`", "\\\\`", `$`, `\\$`, `%`, `%%`)
+	return `"` + r.Replace(arg) + `"`
+}
@@ -0,0 +1,175 @@
+// Package dashboard serves a small web page controlling the tray on
+// localhost, for desktops without a system tray or as a second window.
+package dashboard
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	_ "embed"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// ADDRESS only accepts local connections.
+	ADDRESS = "127.0.0.1"
+	// TOKEN_PARAM carries the token in the page URL, the API calls also
+	// accepting it as a bearer token.
+	TOKEN_PARAM = "token"
+
+	READ_TIMEOUT = 5 * time.Second
+)
+
+//go:embed index.html
+var page []byte
+
+// Task is a running or recent task as shown by the page.
+type Task struct {
+	Id    int       `json:"id"`
+	Label string    `json:"label"`
+	Begin time.Time `json:"begin"`
+	// Elapsed seconds of a running task.
+	Elapsed int `json:"elapsed,omitempty"`
+}
+
+type Day struct {
+	Date    string `json:"date"`
+	Name    string `json:"name"`
+	Seconds int    `json:"seconds"`
+}
+
+// Snapshot is the state the page renders, polled every few seconds.
+type Snapshot struct {
+	Profile string `json:"profile"`
+	Running []Task `json:"running"`
+	Recent  []Task `json:"recent"`
+	Week    []Day  `json:"week"`
+	// WeekTarget in seconds, 0 without weekly_target.
+	WeekTarget int       `json:"weekTarget,omitempty"`
+	Offline    bool      `json:"offline"`
+	Stale      bool      `json:"stale"`
+	Updated    time.Time `json:"updated"`
+	// Failure is the error of the last refresh, empty if it succeeded.
+	Failure string `json:"failure,omitempty"`
+}
+
+// Backend is the tray state and actions, the actions running in the background.
+type Backend interface {
+	Snapshot() Snapshot
+	Stop(id int) error
+	Restart(id int) error
+}
+
+// Server serves the page and its API until Close.
+type Server struct {
+	server   *http.Server
+	listener net.Listener
+	token    string
+	backend  Backend
+}
+
+// NewToken returns a random token, for a dashboard without a configured one.
+func NewToken() string {
+	data := make([]byte, 16)
+	rand.Read(data)
+	return hex.EncodeToString(data)
+}
+
+// Listen serves the dashboard on the local port, requests needing token.
+func Listen(port int, token string, backend Backend) (*Server, error) {
+	listener, err := net.Listen("tcp", net.JoinHostPort(ADDRESS, strconv.Itoa(port)))
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Server{listener: listener, token: token, backend: backend}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", s.servePage)
+	mux.HandleFunc("/api/status", s.serveStatus)
+	mux.HandleFunc("/api/stop", s.action(backend.Stop))
+	mux.HandleFunc("/api/restart", s.action(backend.Restart))
+	s.server = &http.Server{Handler: s.guard(mux), ReadHeaderTimeout: READ_TIMEOUT}
+
+	go func() {
+		if err := s.server.Serve(listener); !errors.Is(err, http.ErrServerClosed) {
+			slog.Warn("dashboard stopped", "err", err)
+		}
+	}()
+	return s, nil
+}
+
+func (s *Server) Close() error {
+	return s.server.Close()
+}
+
+// URL opens the page, with the token.
+func (s *Server) URL() string {
+	return fmt.Sprintf("http://%s/?%s=%s", s.listener.Addr(), TOKEN_PARAM, s.token)
+}
+
+// guard refuses the requests without the token, and the ones naming another
+// host so that a page of another site can't reach the port by DNS rebinding.
+func (s *Server) guard(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host, _, err := net.SplitHostPort(r.Host)
+		if err != nil || (host != ADDRESS && host != "localhost") {
+			http.Error(w, "unknown host", http.StatusForbidden)
+			return
+		}
+
+		token := r.URL.Query().Get(TOKEN_PARAM)
+		if bearer, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok {
+			token = bearer
+		}
+		if subtle.ConstantTimeCompare([]byte(token), []byte(s.token)) != 1 {
+			http.Error(w, "invalid token", http.StatusUnauthorized)
+			return
+		}
+
+		w.Header().Set("X-Frame-Options", "DENY")
+		w.Header().Set("Cache-Control", "no-store")
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (s *Server) servePage(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/" {
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Write(page)
+}
+
+func (s *Server) serveStatus(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(s.backend.Snapshot())
+}
+
+// action runs fn with the id parameter of a POST request.
+func (s *Server) action(fn func(id int) error) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "POST expected", http.StatusMethodNotAllowed)
+			return
+		}
+		id, err := strconv.Atoi(r.URL.Query().Get("id"))
+		if err != nil {
+			http.Error(w, "invalid id", http.StatusBadRequest)
+			return
+		}
+		if err := fn(id); err != nil {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusAccepted)
+	}
+}
@@ -0,0 +1,414 @@
+// Package demo is an in-process fake Kimai server with canned data, to run
+// the tray and the CLI without a real instance.
+package demo
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"qckm/internal/kimai"
+)
+
+const (
+	USERNAME = "demo"
+	TOKEN    = "demo"
+	// USER_ID is the demo user, the others being colleagues with a running timer
+	USER_ID = 1
+)
+
+// Server holds the demo data, changed by the requests it answers.
+type Server struct {
+	mu         sync.Mutex
+	customers  []kimai.Customer
+	projects   []kimai.Project
+	activities []kimai.Activity
+	timesheets []timesheet
+	nextId     int
+	users      []kimai.User
+	// colleagues are the running timesheets of the other users
+	colleagues []kimai.TeamTask
+
+	listener net.Listener
+}
+
+type timesheet struct {
+	kimai.Task
+	begin time.Time
+	end   time.Time
+}
+
+// Start serves the demo API on a free local port.
+func Start() (*Server, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Server{listener: listener}
+	s.seed(time.Now())
+	go http.Serve(listener, s)
+	return s, nil
+}
+
+// URL is the API root to configure the client with.
+func (s *Server) URL() string {
+	return "http://" + s.listener.Addr().String() + "/api/"
+}
+
+func (s *Server) Close() error {
+	return s.listener.Close()
+}
+
+// seed creates the catalogue and this week's timesheets, with a task running
+// for the last 47 minutes.
+func (s *Server) seed(now time.Time) {
+	s.customers = []kimai.Customer{{Id: 1, Name: "ACME"}, {Id: 2, Name: "Globex"}, {Id: 3, Name: "Internal"}}
+	s.projects = []kimai.Project{
+		{Id: 1, Name: "Website", CustomerId: 1},
+		{Id: 2, Name: "Mobile app", CustomerId: 1},
+		{Id: 3, Name: "Support contract", CustomerId: 2},
+		{Id: 4, Name: "Admin", CustomerId: 3},
+	}
+	s.activities = []kimai.Activity{
+		{Id: 1, Name: "Development"},
+		{Id: 2, Name: "Meetings"},
+		{Id: 3, Name: "Design"},
+		{Id: 4, Name: "Code review", ProjectId: 2},
+		{Id: 5, Name: "Hotline", ProjectId: 3},
+	}
+
+	entries := []struct {
+		project, activity int
+		hours             float64
+		description       string
+	}{
+		{1, 1, 2.5, "Landing page"},
+		{2, 4, 1, "Release 1.4"},
+		{1, 2, 0.5, "Weekly"},
+		{3, 5, 1.5, ""},
+		{2, 1, 3, "Offline mode"},
+		{4, 2, 1, "Planning"},
+		{1, 3, 2, "New logo"},
+	}
+	monday := now.AddDate(0, 0, -((int(now.Weekday()) + 6) % 7))
+	day := time.Date(monday.Year(), monday.Month(), monday.Day(), 9, 0, 0, 0, now.Location())
+	for !day.After(now) {
+		begin := day
+		for i := 0; i < 3; i++ {
+			entry := entries[(day.YearDay()+i)%len(entries)]
+			end := begin.Add(time.Duration(entry.hours * float64(time.Hour)))
+			if end.After(now.Add(-time.Hour)) {
+				break
+			}
+			s.add(entry.project, entry.activity, entry.description, begin, end)
+			begin = end.Add(15 * time.Minute)
+		}
+		day = day.AddDate(0, 0, 1)
+	}
+	s.add(2, 1, "Offline mode", now.Add(-47*time.Minute), time.Time{})
+
+	s.users = []kimai.User{
+		{Id: USER_ID, Username: USERNAME},
+		{Id: 2, Username: "alice", Alias: "Alice Martin"},
+		{Id: 3, Username: "bob"},
+	}
+	for _, colleague := range []struct {
+		user              int
+		project, activity int
+		since             time.Duration
+	}{
+		{2, 3, 5, 72 * time.Minute},
+		{3, 1, 3, 20 * time.Minute},
+	} {
+		t := s.build(colleague.project, colleague.activity, "", now.Add(-colleague.since), time.Time{})
+		s.colleagues = append(s.colleagues, kimai.TeamTask{Task: t.task(), User: kimai.User{Id: colleague.user}})
+	}
+}
+
+func (s *Server) add(projectId int, activityId int, description string, begin time.Time, end time.Time) timesheet {
+	t := s.build(projectId, activityId, description, begin, end)
+	s.timesheets = append(s.timesheets, t)
+	return t
+}
+
+// build creates a timesheet with the next id, without adding it.
+func (s *Server) build(projectId int, activityId int, description string, begin time.Time, end time.Time) timesheet {
+	s.nextId++
+	t := timesheet{begin: begin, end: end}
+	t.Id = s.nextId
+	t.Description = description
+	t.Billable = projectId != 4
+	for _, project := range s.projects {
+		if project.Id == projectId {
+			t.Project = project
+		}
+	}
+	for _, activity := range s.activities {
+		if activity.Id == activityId {
+			t.Activity = activity
+		}
+	}
+	return t
+}
+
+// setMeta replaces the custom field of the same name, or adds it.
+func (t *timesheet) setMeta(field kimai.MetaField) {
+	for i, existing := range t.MetaFields {
+		if existing.Name == field.Name {
+			t.MetaFields[i] = field
+			return
+		}
+	}
+	t.MetaFields = append(t.MetaFields, field)
+}
+
+// task renders the timesheet as the API does.
+func (t timesheet) task() kimai.Task {
+	task := t.Task
+	task.StartTime = t.begin.Format(time.RFC3339)
+	if !t.end.IsZero() {
+		task.EndTime = t.end.Format(time.RFC3339)
+		task.Duration = int(t.end.Sub(t.begin).Seconds())
+	}
+	return task
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	path := strings.Trim(strings.TrimPrefix(r.URL.Path, "/api"), "/")
+	parts := strings.Split(path, "/")
+	slog.Debug("demo request", "method", r.Method, "path", path)
+
+	if r.Header.Get("Authorization") != "Bearer "+TOKEN && r.Header.Get("X-AUTH-TOKEN") != TOKEN {
+		writeError(w, http.StatusUnauthorized, "Invalid credentials")
+		return
+	}
+
+	switch {
+	case r.Method == http.MethodGet && path == "version":
+		writeJson(w, kimai.Version{Version: "2.0.0", VersionId: 20000, Copyright: "qckm demo server"})
+	case r.Method == http.MethodGet && path == "customers":
+		writeJson(w, s.customers)
+	case r.Method == http.MethodGet && path == "projects":
+		writeJson(w, s.projects)
+	case r.Method == http.MethodGet && path == "activities":
+		var out []map[string]interface{}
+		for _, activity := range s.activities {
+			entry := map[string]interface{}{"id": activity.Id, "name": activity.Name, "project": nil}
+			if activity.ProjectId > 0 {
+				entry["project"] = activity.ProjectId
+			}
+			out = append(out, entry)
+		}
+		writeJson(w, out)
+	case r.Method == http.MethodGet && path == "timesheets/active":
+		writeJson(w, s.filter(func(t timesheet) bool { return t.end.IsZero() }))
+	case r.Method == http.MethodGet && path == "timesheets/recent":
+		s.recent(w, r)
+	case r.Method == http.MethodGet && path == "users":
+		writeJson(w, s.users)
+	case r.Method == http.MethodGet && path == "timesheets" && r.URL.Query().Get("user") == "all":
+		s.team(w)
+	case r.Method == http.MethodGet && path == "timesheets":
+		s.list(w, r)
+	case r.Method == http.MethodPost && path == "timesheets":
+		s.create(w, r)
+	case r.Method == http.MethodPatch && len(parts) == 3 && parts[0] == "timesheets" && parts[2] == "stop":
+		s.update(w, parts[1], func(t *timesheet) { t.end = time.Now() })
+	case r.Method == http.MethodPatch && len(parts) == 3 && parts[0] == "timesheets" && parts[2] == "restart":
+		s.restart(w, parts[1])
+	case r.Method == http.MethodPatch && len(parts) == 3 && parts[0] == "timesheets" && parts[2] == "meta":
+		var field kimai.MetaField
+		if err := json.NewDecoder(r.Body).Decode(&field); err != nil || field.Name == "" {
+			writeError(w, http.StatusBadRequest, "Invalid meta field")
+			return
+		}
+		s.update(w, parts[1], func(t *timesheet) { t.setMeta(field) })
+	case r.Method == http.MethodPatch && len(parts) == 2 && parts[0] == "timesheets":
+		var fields struct {
+			Description *string `json:"description"`
+			Begin       *string `json:"begin"`
+			End         *string `json:"end"`
+			Billable    *bool   `json:"billable"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&fields); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		s.update(w, parts[1], func(t *timesheet) {
+			if fields.Description != nil {
+				t.Description = *fields.Description
+			}
+			if fields.Billable != nil {
+				t.Billable = *fields.Billable
+			}
+			if fields.Begin != nil {
+				t.begin, _ = kimai.ParseTime(*fields.Begin)
+			}
+			if fields.End != nil {
+				t.end, _ = kimai.ParseTime(*fields.End)
+			}
+		})
+	default:
+		writeError(w, http.StatusNotFound, "Not found")
+	}
+}
+
+func (s *Server) filter(keep func(timesheet) bool) []kimai.Task {
+	tasks := []kimai.Task{}
+	for i := len(s.timesheets) - 1; i >= 0; i-- {
+		if keep(s.timesheets[i]) {
+			tasks = append(tasks, s.timesheets[i].task())
+		}
+	}
+	return tasks
+}
+
+func (s *Server) recent(w http.ResponseWriter, r *http.Request) {
+	size, err := strconv.Atoi(r.URL.Query().Get("size"))
+	if err != nil || size <= 0 {
+		size = 10
+	}
+	tasks := s.filter(func(timesheet) bool { return true })
+	sort.SliceStable(tasks, func(i, j int) bool { return tasks[i].StartTime > tasks[j].StartTime })
+	if len(tasks) > size {
+		tasks = tasks[:size]
+	}
+	writeJson(w, tasks)
+}
+
+func (s *Server) list(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	begin, _ := kimai.ParseTime(query.Get("begin"))
+	end, err := kimai.ParseTime(query.Get("end"))
+	if err != nil {
+		end = time.Now().AddDate(100, 0, 0)
+	}
+	tasks := s.filter(func(t timesheet) bool { return !t.begin.Before(begin) && t.begin.Before(end) })
+
+	size, err := strconv.Atoi(query.Get("size"))
+	if err != nil || size <= 0 {
+		size = 50
+	}
+	page, err := strconv.Atoi(query.Get("page"))
+	if err != nil || page <= 0 {
+		page = 1
+	}
+	from := (page - 1) * size
+	if from >= len(tasks) && page > 1 {
+		writeError(w, http.StatusNotFound, "Not found")
+		return
+	}
+	if from > len(tasks) {
+		from = len(tasks)
+	}
+	to := from + size
+	if to > len(tasks) {
+		to = len(tasks)
+	}
+	writeJson(w, tasks[from:to])
+}
+
+// team answers the running timesheets of all the users, with the user as an
+// id like Kimai 2 does.
+func (s *Server) team(w http.ResponseWriter) {
+	type entry struct {
+		kimai.Task
+		User int `json:"user"`
+	}
+	out := []entry{}
+	for _, task := range s.filter(func(t timesheet) bool { return t.end.IsZero() }) {
+		out = append(out, entry{Task: task, User: USER_ID})
+	}
+	for _, colleague := range s.colleagues {
+		out = append(out, entry{Task: colleague.Task, User: colleague.User.Id})
+	}
+	writeJson(w, out)
+}
+
+func (s *Server) create(w http.ResponseWriter, r *http.Request) {
+	var payload struct {
+		Project     int    `json:"project"`
+		Activity    int    `json:"activity"`
+		Description string `json:"description"`
+		Billable    *bool  `json:"billable"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	s.stopActive()
+	t := s.add(payload.Project, payload.Activity, payload.Description, time.Now(), time.Time{})
+	if t.Project.Id == 0 || t.Activity.Id == 0 {
+		s.timesheets = s.timesheets[:len(s.timesheets)-1]
+		writeError(w, http.StatusBadRequest, "Unknown project or activity")
+		return
+	}
+	if payload.Billable != nil {
+		s.timesheets[len(s.timesheets)-1].Billable = *payload.Billable
+	}
+	writeJson(w, s.timesheets[len(s.timesheets)-1].task())
+}
+
+func (s *Server) restart(w http.ResponseWriter, id string) {
+	t := s.find(id)
+	if t == nil {
+		writeError(w, http.StatusNotFound, "Not found")
+		return
+	}
+	projectId, activityId := t.Project.Id, t.Activity.Id
+
+	s.stopActive()
+	writeJson(w, s.add(projectId, activityId, "", time.Now(), time.Time{}).task())
+}
+
+func (s *Server) update(w http.ResponseWriter, id string, change func(*timesheet)) {
+	t := s.find(id)
+	if t == nil {
+		writeError(w, http.StatusNotFound, "Not found")
+		return
+	}
+	change(t)
+	writeJson(w, t.task())
+}
+
+func (s *Server) find(id string) *timesheet {
+	for i := range s.timesheets {
+		if strconv.Itoa(s.timesheets[i].Id) == id {
+			return &s.timesheets[i]
+		}
+	}
+	return nil
+}
+
+// stopActive stops the running timesheets, as Kimai does by default when
+// another one is started.
+func (s *Server) stopActive() {
+	for i := range s.timesheets {
+		if s.timesheets[i].end.IsZero() {
+			s.timesheets[i].end = time.Now()
+		}
+	}
+}
+
+func writeJson(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	fmt.Fprintf(w, `{"code": %d, "message": %q}`, status, message)
+}
@@ -0,0 +1,78 @@
+package desktop
+
+import (
+	"errors"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// Choose asks the user to pick one of options and returns its index, using
+// zenity/kdialog on Linux, osascript on macOS and Out-GridView on Windows.
+func Choose(title string, text string, options []string) (int, error) {
+	if len(options) == 0 {
+		return -1, ErrCancelled
+	}
+
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		items := make([]string, len(options))
+		for i, option := range options {
+			items[i] = appleScriptString(option)
+		}
+		script := `choose from list {` + strings.Join(items, ", ") + `} with title ` + appleScriptString(title) +
+			` with prompt ` + appleScriptString(text)
+		cmd = exec.Command("osascript", "-e", script)
+	case "windows":
+		items := make([]string, len(options))
+		for i, option := range options {
+			items[i] = powerShellString(option)
+		}
+		script := `@(` + strings.Join(items, ", ") + `) | Out-GridView -Title ` + powerShellString(title+" — "+text) +
+			` -OutputMode Single`
+		cmd = exec.Command("powershell", "-NoProfile", "-Command", script)
+	default:
+		if _, err := exec.LookPath("zenity"); err == nil {
+			args := []string{"--list", "--title", title, "--text", text, "--column", "index", "--column", "", "--hide-column", "1", "--print-column", "1"}
+			for i, option := range options {
+				args = append(args, strconv.Itoa(i), option)
+			}
+			cmd = exec.Command("zenity", args...)
+		} else {
+			args := []string{"--title", title, "--menu", text}
+			for i, option := range options {
+				args = append(args, strconv.Itoa(i), option)
+			}
+			cmd = exec.Command("kdialog", args...)
+		}
+	}
+
+	out, err := cmd.Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return -1, ErrCancelled
+		}
+		return -1, err
+	}
+
+	answer := strings.TrimRight(string(out), "\r\n")
+	switch runtime.GOOS {
+	case "darwin", "windows":
+		// both answer with the option itself, "false" when cancelled on macOS
+		for i, option := range options {
+			if option == answer {
+				return i, nil
+			}
+		}
+		return -1, ErrCancelled
+	default:
+		i, err := strconv.Atoi(answer)
+		if err != nil || i < 0 || i >= len(options) {
+			return -1, ErrCancelled
+		}
+		return i, nil
+	}
+}
@@ -0,0 +1,40 @@
+package desktop
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// Copy puts text in the clipboard, using wl-copy, xclip or xsel on Linux,
+// pbcopy on macOS and clip on Windows.
+func Copy(text string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("pbcopy")
+	case "windows":
+		cmd = exec.Command("clip")
+	default:
+		switch {
+		case os.Getenv("WAYLAND_DISPLAY") != "" && hasCommand("wl-copy"):
+			cmd = exec.Command("wl-copy")
+		case hasCommand("xclip"):
+			cmd = exec.Command("xclip", "-selection", "clipboard")
+		case hasCommand("xsel"):
+			cmd = exec.Command("xsel", "--clipboard", "--input")
+		default:
+			return errors.New("no clipboard tool found, install wl-clipboard, xclip or xsel")
+		}
+	}
+
+	cmd.Stdin = strings.NewReader(text)
+	return cmd.Run()
+}
+
+func hasCommand(name string) bool {
+	_, err := exec.LookPath(name)
+	return err == nil
+}
@@ -0,0 +1,48 @@
+package desktop
+
+import (
+	"errors"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// Confirm asks a yes/no question, true means yes.
+func Confirm(title string, text string) (bool, error) {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		script := `button returned of (display dialog ` + appleScriptString(text) +
+			` with title ` + appleScriptString(title) +
+			` buttons {"No", "Yes"} default button "Yes")`
+		cmd = exec.Command("osascript", "-e", script)
+	case "windows":
+		script := `Add-Type -AssemblyName System.Windows.Forms; ` +
+			`[System.Windows.Forms.MessageBox]::Show(` + powerShellString(text) + `, ` +
+			powerShellString(title) + `, 'YesNo', 'Question')`
+		cmd = exec.Command("powershell", "-NoProfile", "-Command", script)
+	default:
+		if _, err := exec.LookPath("zenity"); err == nil {
+			cmd = exec.Command("zenity", "--question", "--title", title, "--text", text)
+		} else {
+			cmd = exec.Command("kdialog", "--title", title, "--yesno", text)
+		}
+	}
+
+	out, err := cmd.Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			// zenity/kdialog answer "no" with exit status 1, osascript when the dialog is closed
+			return false, nil
+		}
+		return false, err
+	}
+
+	switch runtime.GOOS {
+	case "darwin", "windows":
+		return strings.TrimSpace(string(out)) == "Yes", nil
+	default:
+		return true, nil
+	}
+}
@@ -0,0 +1,29 @@
+package desktop
+
+import (
+	"os/exec"
+	"runtime"
+)
+
+// Notify shows a desktop notification, using notify-send on Linux, osascript
+// on macOS and a PowerShell balloon tip on Windows.
+func Notify(title string, message string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		script := `display notification ` + appleScriptString(message) + ` with title ` + appleScriptString(title)
+		cmd = exec.Command("osascript", "-e", script)
+	case "windows":
+		script := `Add-Type -AssemblyName System.Windows.Forms; ` +
+			`$n = New-Object System.Windows.Forms.NotifyIcon; ` +
+			`$n.Icon = [System.Drawing.SystemIcons]::Information; $n.Visible = $true; ` +
+			`$n.ShowBalloonTip(5000, ` + powerShellString(title) + `, ` + powerShellString(message) + `, 'Info'); ` +
+			`Start-Sleep -Seconds 5; $n.Dispose()`
+		cmd = exec.Command("powershell", "-NoProfile", "-Command", script)
+		// the balloon only lives as long as the PowerShell process
+		return cmd.Start()
+	default:
+		cmd = exec.Command("notify-send", "--app-name=qckm", title, message)
+	}
+	return cmd.Run()
+}
@@ -0,0 +1,26 @@
+package desktop
+
+import (
+	"os/exec"
+	"runtime"
+)
+
+// OpenURL opens url in the default browser, using xdg-open on Linux, open on
+// macOS and the URL protocol handler on Windows.
+func OpenURL(url string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("open", url)
+	case "windows":
+		cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", url)
+	default:
+		cmd = exec.Command("xdg-open", url)
+	}
+	// the browser may keep running, don't block on it
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	go cmd.Wait()
+	return nil
+}
@@ -0,0 +1,38 @@
+package desktop
+
+import (
+	"errors"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// PromptPassword is Prompt with the typed text hidden where the platform allows it.
+func PromptPassword(title string, text string) (string, error) {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		script := `text returned of (display dialog ` + appleScriptString(text) +
+			` with title ` + appleScriptString(title) + ` default answer "" with hidden answer)`
+		cmd = exec.Command("osascript", "-e", script)
+	case "windows":
+		// InputBox can't mask the input
+		return Prompt(title, text, "")
+	default:
+		if _, err := exec.LookPath("zenity"); err == nil {
+			cmd = exec.Command("zenity", "--password", "--title", title)
+		} else {
+			cmd = exec.Command("kdialog", "--title", title, "--password", text)
+		}
+	}
+
+	out, err := cmd.Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return "", ErrCancelled
+		}
+		return "", err
+	}
+	return strings.TrimRight(string(out), "\r\n"), nil
+}
@@ -0,0 +1,61 @@
+// Package desktop wraps the native desktop helpers (dialogs, ...) qckm shells out to.
+package desktop
+
+import (
+	"errors"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// ErrCancelled is returned when the user dismissed a dialog.
+var ErrCancelled = errors.New("dialog cancelled")
+
+// Prompt asks the user for a line of text, using zenity/kdialog on Linux,
+// osascript on macOS and PowerShell on Windows.
+func Prompt(title string, text string, defaultValue string) (string, error) {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		script := `text returned of (display dialog ` + appleScriptString(text) +
+			` with title ` + appleScriptString(title) +
+			` default answer ` + appleScriptString(defaultValue) + `)`
+		cmd = exec.Command("osascript", "-e", script)
+	case "windows":
+		script := `Add-Type -AssemblyName Microsoft.VisualBasic; ` +
+			`[Microsoft.VisualBasic.Interaction]::InputBox(` + powerShellString(text) +
+			`, ` + powerShellString(title) + `, ` + powerShellString(defaultValue) + `)`
+		cmd = exec.Command("powershell", "-NoProfile", "-Command", script)
+	default:
+		if _, err := exec.LookPath("zenity"); err == nil {
+			cmd = exec.Command("zenity", "--entry", "--title", title, "--text", text, "--entry-text", defaultValue)
+		} else {
+			cmd = exec.Command("kdialog", "--title", title, "--inputbox", text, defaultValue)
+		}
+	}
+
+	out, err := cmd.Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return "", ErrCancelled
+		}
+		return "", err
+	}
+
+	value := strings.TrimRight(string(out), "\r\n")
+	// InputBox has no cancel status, an empty answer is the closest thing
+	if runtime.GOOS == "windows" && value == "" {
+		return "", ErrCancelled
+	}
+	return value, nil
+}
+
+func appleScriptString(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	return `"` + strings.ReplaceAll(s, `"`, `\"`) + `"`
+}
+
+func powerShellString(s string) string {
+	return `'` + strings.ReplaceAll(s, `'`, `''`) + `'`
+}
@@ -0,0 +1,152 @@
+// Package export writes timesheets as CSV or JSON for reports and invoices,
+// or as an ICS calendar to overlay them in a calendar app.
+package export
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"qckm/internal/kimai"
+	"qckm/internal/stats"
+)
+
+const (
+	CSV  = "csv"
+	JSON = "json"
+	ICS  = "ics"
+
+	TODAY      = "today"
+	THIS_WEEK  = "week"
+	LAST_WEEK  = "last-week"
+	THIS_MONTH = "month"
+)
+
+// FORMATS are the formats accepted by Write.
+var FORMATS = []string{CSV, JSON, ICS}
+
+// RANGES are the names accepted by Range, in menu order.
+var RANGES = []string{TODAY, THIS_WEEK, LAST_WEEK, THIS_MONTH}
+
+// DATE_RANGE_SEPARATOR separates the first and last days of a range, e.g.
+// 2024-05-01..2024-05-31.
+const DATE_RANGE_SEPARATOR = ".."
+
+// Range returns the [from, to) interval of a named range around now, of a
+// day (2024-05-02) or of the days between two dates, both included.
+func Range(name string, now time.Time) (from time.Time, to time.Time, err error) {
+	switch name {
+	case TODAY:
+		from = stats.StartOfDay(now)
+		return from, from.AddDate(0, 0, 1), nil
+	case THIS_WEEK:
+		from = stats.StartOfWeek(now)
+		return from, from.AddDate(0, 0, 7), nil
+	case LAST_WEEK:
+		to = stats.StartOfWeek(now)
+		return to.AddDate(0, 0, -7), to, nil
+	case THIS_MONTH:
+		from = time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+		return from, from.AddDate(0, 1, 0), nil
+	}
+
+	first, last, found := strings.Cut(name, DATE_RANGE_SEPARATOR)
+	if !found {
+		last = first
+	}
+	from, err = time.ParseInLocation("2006-01-02", first, now.Location())
+	if err == nil {
+		to, err = time.ParseInLocation("2006-01-02", last, now.Location())
+	}
+	if err != nil || to.Before(from) {
+		return from, to, fmt.Errorf("unknown range %q, expected one of %v, a day or first%slast days like 2024-05-01..2024-05-31", name, RANGES, DATE_RANGE_SEPARATOR)
+	}
+	return from, to.AddDate(0, 0, 1), nil
+}
+
+// Entry is an exported timesheet, flattened for spreadsheets.
+type Entry struct {
+	Id          int      `json:"id"`
+	Date        string   `json:"date"`
+	Begin       string   `json:"begin"`
+	End         string   `json:"end,omitempty"`
+	Hours       float64  `json:"hours"`
+	Project     string   `json:"project"`
+	Activity    string   `json:"activity"`
+	Description string   `json:"description,omitempty"`
+	Tags        []string `json:"tags,omitempty"`
+	Billable    bool     `json:"billable"`
+	// Meta are the custom fields set, by name.
+	Meta map[string]string `json:"meta,omitempty"`
+}
+
+// Entries converts the tasks, running ones counting until now.
+func Entries(tasks []kimai.Task) []Entry {
+	entries := make([]Entry, 0, len(tasks))
+	for _, task := range tasks {
+		begin := task.Begin()
+		entry := Entry{
+			Id:          task.Id,
+			Date:        begin.Format("2006-01-02"),
+			Begin:       begin.Format("15:04"),
+			Hours:       float64(int(task.Spent().Minutes())) / 60,
+			Project:     task.Project.Name,
+			Activity:    task.Activity.Name,
+			Description: task.Description,
+			Tags:        task.Tags,
+			Billable:    task.Billable,
+		}
+		if !task.Running() {
+			entry.End = task.End().Format("15:04")
+		}
+		for _, field := range task.MetaFields {
+			if field.Value == "" {
+				continue
+			}
+			if entry.Meta == nil {
+				entry.Meta = map[string]string{}
+			}
+			entry.Meta[field.Name] = field.Value
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// Write writes the tasks to w in the given format. The CSV has a column for
+// each custom field named in meta, after the others.
+func Write(w io.Writer, format string, tasks []kimai.Task, meta []string) error {
+	switch format {
+	case CSV:
+		return writeCSV(w, Entries(tasks), meta)
+	case JSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(Entries(tasks))
+	case ICS:
+		return writeICS(w, tasks, time.Now())
+	}
+	return fmt.Errorf("unknown export format %q, expected one of %v", format, FORMATS)
+}
+
+func writeCSV(w io.Writer, entries []Entry, meta []string) error {
+	out := csv.NewWriter(w)
+	header := []string{"id", "date", "begin", "end", "hours", "project", "activity", "description", "tags", "billable"}
+	out.Write(append(header, meta...))
+	for _, e := range entries {
+		row := []string{
+			strconv.Itoa(e.Id), e.Date, e.Begin, e.End, strconv.FormatFloat(e.Hours, 'f', 2, 64),
+			e.Project, e.Activity, e.Description, strings.Join(e.Tags, ","), strconv.FormatBool(e.Billable),
+		}
+		for _, name := range meta {
+			row = append(row, e.Meta[name])
+		}
+		out.Write(row)
+	}
+	out.Flush()
+	return out.Error()
+}
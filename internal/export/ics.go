@@ -0,0 +1,81 @@
+package export
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"qckm/internal/kimai"
+)
+
+const (
+	ICS_PRODID = "-//qckm//Kimai timesheets//EN"
+	// ICS_LINE_LENGTH is the maximum line length in octets, longer ones are folded.
+	ICS_LINE_LENGTH = 75
+	ICS_TIME_FORMAT = "20060102T150405Z"
+)
+
+var icsEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+
+// writeICS writes one calendar event per task, running ones ending now.
+func writeICS(w io.Writer, tasks []kimai.Task, now time.Time) error {
+	out := bufio.NewWriter(w)
+	line := func(name string, value string) {
+		writeICSLine(out, name+":"+value)
+	}
+
+	line("BEGIN", "VCALENDAR")
+	line("VERSION", "2.0")
+	line("PRODID", ICS_PRODID)
+	line("CALSCALE", "GREGORIAN")
+	for _, task := range tasks {
+		end := now
+		if !task.Running() {
+			end = task.End()
+		}
+		description := task.Description
+		if len(task.Tags) > 0 {
+			description = strings.TrimSpace(description + "\n#" + strings.Join(task.Tags, " #"))
+		}
+
+		line("BEGIN", "VEVENT")
+		line("UID", fmt.Sprintf("timesheet-%d@qckm", task.Id))
+		line("DTSTAMP", now.UTC().Format(ICS_TIME_FORMAT))
+		line("DTSTART", task.Begin().UTC().Format(ICS_TIME_FORMAT))
+		line("DTEND", end.UTC().Format(ICS_TIME_FORMAT))
+		line("SUMMARY", icsEscaper.Replace(task.TextOutput()))
+		if description != "" {
+			line("DESCRIPTION", icsEscaper.Replace(description))
+		}
+		if len(task.Tags) > 0 {
+			tags := make([]string, 0, len(task.Tags))
+			for _, tag := range task.Tags {
+				tags = append(tags, icsEscaper.Replace(tag))
+			}
+			line("CATEGORIES", strings.Join(tags, ","))
+		}
+		line("END", "VEVENT")
+	}
+	line("END", "VCALENDAR")
+	return out.Flush()
+}
+
+// writeICSLine ends the line with CRLF, folding it every ICS_LINE_LENGTH
+// octets without splitting a UTF-8 sequence.
+func writeICSLine(out *bufio.Writer, line string) {
+	limit := ICS_LINE_LENGTH
+	for len(line) > limit {
+		cut := limit
+		// back to the start of a UTF-8 sequence
+		for cut > 0 && line[cut]&0xC0 == 0x80 {
+			cut--
+		}
+		out.WriteString(line[:cut] + "\r\n ")
+		line = line[cut:]
+		// the continuation lines start with a space
+		limit = ICS_LINE_LENGTH - 1
+	}
+	out.WriteString(line + "\r\n")
+}
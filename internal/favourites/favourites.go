@@ -0,0 +1,102 @@
+// Package favourites keeps the project and activity pairs pinned by the user.
+package favourites
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"qckm/internal/kimai"
+)
+
+// Favourite keeps the names along the ids so it can be shown before the
+// projects and activities are fetched. Billable and the rates can be set by
+// editing the file, they override the config defaults.
+type Favourite struct {
+	Project    kimai.Project  `json:"project"`
+	Activity   kimai.Activity `json:"activity"`
+	Billable   *bool          `json:"billable,omitempty"`
+	FixedRate  *float64       `json:"fixed_rate,omitempty"`
+	HourlyRate *float64       `json:"hourly_rate,omitempty"`
+}
+
+func (f Favourite) TextOutput() string {
+	return fmt.Sprintf("[%s] %s", f.Project.Name, f.Activity.Name)
+}
+
+// Store is the list of favourites persisted as a JSON file.
+type Store struct {
+	path       string
+	mu         sync.Mutex
+	favourites []Favourite
+}
+
+// Open loads the favourites at path, a missing file is an empty list.
+func Open(path string) (*Store, error) {
+	s := &Store{path: path}
+
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &s.favourites); err != nil {
+		return nil, fmt.Errorf("corrupted favourites %s: %w", path, err)
+	}
+	return s, nil
+}
+
+// List returns the favourites in the order they were pinned.
+func (s *Store) List() []Favourite {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]Favourite(nil), s.favourites...)
+}
+
+func (s *Store) Has(projectId int, activityId int) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.index(projectId, activityId) >= 0
+}
+
+// Toggle pins the pair, or unpins it if it already is a favourite. pinned
+// is the new state.
+func (s *Store) Toggle(project kimai.Project, activity kimai.Activity) (pinned bool, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if i := s.index(project.Id, activity.Id); i >= 0 {
+		s.favourites = append(s.favourites[:i:i], s.favourites[i+1:]...)
+	} else {
+		s.favourites = append(s.favourites, Favourite{Project: project, Activity: activity})
+		pinned = true
+	}
+	return pinned, s.save()
+}
+
+func (s *Store) index(projectId int, activityId int) int {
+	for i, favourite := range s.favourites {
+		if favourite.Project.Id == projectId && favourite.Activity.Id == activityId {
+			return i
+		}
+	}
+	return -1
+}
+
+func (s *Store) save() error {
+	data, err := json.MarshalIndent(s.favourites, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0700); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(s.path, data, 0600)
+}
@@ -0,0 +1,68 @@
+// Package fuzzy ranks strings against a typed filter, the letters of the
+// filter having to appear in order but not next to each other.
+package fuzzy
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+const (
+	MATCH_SCORE       = 1
+	CONSECUTIVE_BONUS = 5
+	WORD_START_BONUS  = 3
+)
+
+// Score is how well text matches pattern, ignoring case and spaces in the
+// pattern. ok is false when some letter of pattern is missing.
+func Score(pattern string, text string) (score int, ok bool) {
+	letters := []rune(strings.ToLower(strings.Join(strings.Fields(pattern), "")))
+	if len(letters) == 0 {
+		return 0, true
+	}
+
+	runes := []rune(strings.ToLower(text))
+	next := 0
+	last := -2
+	for i, r := range runes {
+		if r != letters[next] {
+			continue
+		}
+		score += MATCH_SCORE
+		if i == last+1 {
+			score += CONSECUTIVE_BONUS
+		}
+		if i == 0 || !unicode.IsLetter(runes[i-1]) && !unicode.IsDigit(runes[i-1]) {
+			score += WORD_START_BONUS
+		}
+		last = i
+		next++
+		if next == len(letters) {
+			return score, true
+		}
+	}
+	return 0, false
+}
+
+// Filter returns the indexes of the items matching pattern, the best first
+// and the shorter first on equal scores.
+func Filter(pattern string, items []string) []int {
+	var matches []int
+	scores := map[int]int{}
+	for i, item := range items {
+		if score, ok := Score(pattern, item); ok {
+			matches = append(matches, i)
+			scores[i] = score
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		a, b := matches[i], matches[j]
+		if scores[a] != scores[b] {
+			return scores[a] > scores[b]
+		}
+		return len(items[a]) < len(items[b])
+	})
+	return matches
+}
@@ -0,0 +1,176 @@
+// Package history keeps the fetched timesheets in a local bbolt database, so
+// the menus are filled at startup and the stats still computed offline.
+package history
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+
+	"qckm/internal/kimai"
+)
+
+const (
+	TIMESHEETS_BUCKET = "timesheets"
+	META_BUCKET       = "meta"
+	SNAPSHOT_KEY      = "snapshot"
+	// OPEN_TIMEOUT gives up on a database locked by another process.
+	OPEN_TIMEOUT = time.Second
+)
+
+// Store is the history of a profile, timesheets keyed by id.
+type Store struct {
+	db *bolt.DB
+}
+
+// Snapshot is what the menus show besides the timesheets of the week.
+type Snapshot struct {
+	Recent  []kimai.Task `json:"recent"`
+	Running []kimai.Task `json:"running"`
+	// Updated is the last complete refresh.
+	Updated time.Time `json:"updated"`
+}
+
+func Open(path string) (*Store, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, err
+	}
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: OPEN_TIMEOUT})
+	if err != nil {
+		return nil, err
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		for _, name := range []string{TIMESHEETS_BUCKET, META_BUCKET} {
+			if _, err := tx.CreateBucketIfNotExists([]byte(name)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return &Store{db: db}, nil
+}
+
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// Sync replaces the timesheets starting between begin and end with tasks,
+// the ones deleted on the server going away as well.
+func (s *Store) Sync(begin time.Time, end time.Time, tasks []kimai.Task) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(TIMESHEETS_BUCKET))
+		err := each(tx, func(key []byte, task kimai.Task) error {
+			if start := task.Begin(); !start.Before(begin) && start.Before(end) {
+				return bucket.Delete(key)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, task := range tasks {
+			data, err := json.Marshal(task)
+			if err != nil {
+				return err
+			}
+			if err := bucket.Put(idKey(task.Id), data); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Between returns the timesheets starting between begin and end.
+func (s *Store) Between(begin time.Time, end time.Time) ([]kimai.Task, error) {
+	var tasks []kimai.Task
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return each(tx, func(key []byte, task kimai.Task) error {
+			if start := task.Begin(); !start.Before(begin) && start.Before(end) {
+				tasks = append(tasks, task)
+			}
+			return nil
+		})
+	})
+	return tasks, err
+}
+
+// Prune drops the timesheets started before the given time.
+func (s *Store) Prune(before time.Time) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(TIMESHEETS_BUCKET))
+		return each(tx, func(key []byte, task kimai.Task) error {
+			if task.Begin().Before(before) {
+				return bucket.Delete(key)
+			}
+			return nil
+		})
+	})
+}
+
+func (s *Store) SaveSnapshot(snapshot Snapshot) error {
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return err
+	}
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket([]byte(META_BUCKET)).Put([]byte(SNAPSHOT_KEY), data)
+	})
+}
+
+// Snapshot returns the last saved snapshot, empty if none.
+func (s *Store) Snapshot() (Snapshot, error) {
+	var snapshot Snapshot
+	err := s.db.View(func(tx *bolt.Tx) error {
+		data := tx.Bucket([]byte(META_BUCKET)).Get([]byte(SNAPSHOT_KEY))
+		if data == nil {
+			return nil
+		}
+		return json.Unmarshal(data, &snapshot)
+	})
+	return snapshot, err
+}
+
+// each calls fn with the timesheets in id order. The keys are collected
+// first, fn being allowed to delete them.
+func each(tx *bolt.Tx, fn func(key []byte, task kimai.Task) error) error {
+	bucket := tx.Bucket([]byte(TIMESHEETS_BUCKET))
+	var keys [][]byte
+	var tasks []kimai.Task
+	err := bucket.ForEach(func(key []byte, data []byte) error {
+		var task kimai.Task
+		if err := json.Unmarshal(data, &task); err != nil {
+			return err
+		}
+		keys = append(keys, append([]byte(nil), key...))
+		tasks = append(tasks, task)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for i, key := range keys {
+		if err := fn(key, tasks[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// idKey sorts the timesheets by id in the bucket.
+func idKey(id int) []byte {
+	key := make([]byte, 8)
+	binary.BigEndian.PutUint64(key, uint64(id))
+	return key
+}
@@ -0,0 +1,61 @@
+// Package hooks runs the shell commands configured for events and custom
+// menu entries.
+package hooks
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+)
+
+const (
+	STARTED        = "started"
+	STOPPED        = "stopped"
+	REFRESH_FAILED = "refresh_failed"
+
+	// TIMEOUT kills the commands still running after it.
+	TIMEOUT = 30 * time.Second
+	// ENV_PREFIX starts the name of the variables given to the commands.
+	ENV_PREFIX = "QCKM_"
+)
+
+// EVENTS are the events a hook can be set for.
+var EVENTS = []string{STARTED, STOPPED, REFRESH_FAILED}
+
+// Run runs command with sh -c, or cmd /C on Windows, env being added to the
+// environment with ENV_PREFIX, e.g. QCKM_PROJECT, see EnvName. It returns the
+// output, stderr included.
+func Run(ctx context.Context, command string, env map[string]string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, TIMEOUT)
+	defer cancel()
+
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "cmd", "/C", command)
+	} else {
+		cmd = exec.CommandContext(ctx, "sh", "-c", command)
+	}
+	cmd.Env = os.Environ()
+	for key, value := range env {
+		cmd.Env = append(cmd.Env, EnvName(key)+"="+value)
+	}
+	return cmd.CombinedOutput()
+}
+
+// EnvName is the variable key is given as: ENV_PREFIX and key upper-cased,
+// other characters than letters and digits replaced by _, e.g. "meta_Cost
+// center" is QCKM_META_COST_CENTER.
+func EnvName(key string) string {
+	return ENV_PREFIX + strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return r
+		}
+		return '_'
+	}, key)
+}
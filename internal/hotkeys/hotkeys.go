@@ -0,0 +1,57 @@
+// Package hotkeys registers global keyboard shortcuts written like
+// "ctrl+alt+s", on top of golang.design/x/hotkey.
+package hotkeys
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.design/x/hotkey"
+)
+
+// ErrUnsupported is returned when the build has no hotkey support (no cgo).
+var ErrUnsupported = errors.New("global hotkeys are not supported by this build")
+
+// Parse splits a shortcut like "ctrl+shift+F5" in its modifiers and key.
+func Parse(spec string) ([]hotkey.Modifier, hotkey.Key, error) {
+	if !supported {
+		return nil, 0, ErrUnsupported
+	}
+	parts := strings.Split(strings.ToLower(strings.ReplaceAll(spec, " ", "")), "+")
+	name := parts[len(parts)-1]
+	key, ok := keys[name]
+	if !ok {
+		return nil, 0, fmt.Errorf("hotkey %q: unknown key %q", spec, name)
+	}
+
+	var mods []hotkey.Modifier
+	for _, name := range parts[:len(parts)-1] {
+		mod, ok := modifiers[name]
+		if !ok {
+			return nil, 0, fmt.Errorf("hotkey %q: unknown modifier %q", spec, name)
+		}
+		mods = append(mods, mod)
+	}
+	return mods, key, nil
+}
+
+// Register grabs the shortcut and runs action, in its own goroutine, each
+// time it is pressed.
+func Register(spec string, action func()) (*hotkey.Hotkey, error) {
+	mods, key, err := Parse(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	hk := hotkey.New(mods, key)
+	if err := hk.Register(); err != nil {
+		return nil, fmt.Errorf("hotkey %q: %w", spec, err)
+	}
+	go func() {
+		for range hk.Keydown() {
+			action()
+		}
+	}()
+	return hk, nil
+}
@@ -0,0 +1,13 @@
+//go:build cgo
+
+package hotkeys
+
+import "golang.design/x/hotkey"
+
+// X11 has no fixed alt/super masks, Mod1 and Mod4 are the usual mapping.
+var modifiers = map[string]hotkey.Modifier{
+	"ctrl":  hotkey.ModCtrl,
+	"shift": hotkey.ModShift,
+	"alt":   hotkey.Mod1,
+	"super": hotkey.Mod4,
+}
@@ -0,0 +1,11 @@
+package hotkeys
+
+import "golang.design/x/hotkey"
+
+var modifiers = map[string]hotkey.Modifier{
+	"ctrl":  hotkey.ModCtrl,
+	"shift": hotkey.ModShift,
+	"alt":   hotkey.ModAlt,
+	"super": hotkey.ModWin,
+	"win":   hotkey.ModWin,
+}
@@ -0,0 +1,12 @@
+//go:build !windows && !cgo
+
+package hotkeys
+
+import "golang.design/x/hotkey"
+
+const supported = false
+
+var (
+	keys      = map[string]hotkey.Key{}
+	modifiers = map[string]hotkey.Modifier{}
+)
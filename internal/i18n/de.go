@@ -0,0 +1,193 @@
+package i18n
+
+var de = map[string]string{
+	// menu
+	"Active":     "Aktiv",
+	"Active: %s": "Aktiv: %s",
+	"Authentication failed — log in again…": "Anmeldung abgelehnt — erneut anmelden…",
+	"Autostart at login":                    "Beim Anmelden starten",
+	"Copy to clipboard":                     "In die Zwischenablage kopieren",
+	"Edit description…":                     "Beschreibung bearbeiten…",
+	"Enter a new API token":                 "Neues API-Token eingeben",
+	"Export timesheets as %s":               "Zeiteinträge als %s exportieren",
+	"Export…":                               "Exportieren…",
+	"Favourites":                            "Favoriten",
+	"Last week":                             "Letzte Woche",
+	"Offline":                               "Offline",
+	"Offline — %d queued action(s)":         "Offline — %d Aktion(en) in der Warteschlange",
+	"Open in Kimai":                         "In Kimai öffnen",
+	"Open the Kimai web interface":          "Die Weboberfläche von Kimai öffnen",
+	"Other":                                 "Sonstige",
+	"Pause":                                 "Pausieren",
+	"Pin to favourites":                     "Zu den Favoriten hinzufügen",
+	"Quit":                                  "Beenden",
+	"Quit the whole app":                    "Die Anwendung beenden",
+	"Read the config file again":            "Die Konfigurationsdatei neu einlesen",
+	"Recent":                                "Zuletzt",
+	"Refresh":                               "Aktualisieren",
+	"Refresh the menu":                      "Das Menü aktualisieren",
+	"Reload config":                         "Konfiguration neu laden",
+	"Resume %s":                             "%s fortsetzen",
+	"Save to %s":                            "In %s speichern",
+	"Search all projects…":                  "Alle Projekte durchsuchen…",
+	"Start a new task":                      "Eine neue Aufgabe starten",
+	"Start a pinned task":                   "Eine Favoriten-Aufgabe starten",
+	"Start a task on any project, filtered by name": "Eine Aufgabe in einem beliebigen Projekt starten, nach Namen gefiltert",
+	"Start new…":                        "Neu starten…",
+	"Start pomodoro":                    "Pomodoro starten",
+	"Start qckm when you log in":        "qckm beim Anmelden starten",
+	"Stop":                              "Stoppen",
+	"Stop all (%d)":                     "Alle stoppen (%d)",
+	"Stop pomodoro (%s)":                "Pomodoro stoppen (%s)",
+	"Switch profile":                    "Profil wechseln",
+	"The Kimai server can't be reached": "Der Kimai-Server ist nicht erreichbar",
+	"This month":                        "Dieser Monat",
+	"This week":                         "Diese Woche",
+	"This week: %s":                     "Diese Woche: %s",
+	"Time tracked this week":            "Diese Woche erfasste Zeit",
+	"Time tracked today":                "Heute erfasste Zeit",
+	"Timesheets":                        "Zeiteinträge",
+	"Today":                             "Heute",
+	"Today: %s":                         "Heute: %s",
+	"Unpin from favourites":             "Aus den Favoriten entfernen",
+	"Use another Kimai instance":        "Eine andere Kimai-Instanz verwenden",
+	"(offline)":                         "(offline)",
+	"offline":                           "offline",
+	"Team":                              "Team",
+	"Team (%d)":                         "Team (%d)",
+	"Running timers of all the users":   "Laufende Timer aller Benutzer",
+	"Nobody is tracking time":           "Niemand erfasst gerade Zeit",
+	"Copy":                              "Kopieren",
+	"Copy a task summary, timesheet ID or link": "Zusammenfassung, ID oder Link einer Aufgabe kopieren",
+	"Summary":                    "Zusammenfassung",
+	"Summary of the active task": "Zusammenfassung der aktiven Aufgabe",
+	"Timesheet ID (%d)":          "Zeiteintrag-ID (%d)",
+	"Link":                       "Link",
+	"Start suggested":            "Vorschlag starten",
+	"Start the task matching the git branch or window":     "Die zum Git-Branch oder Fenster passende Aufgabe starten",
+	"Start suggested: [%s] %s":                             "Vorschlag starten: [%s] %s",
+	"Save as calendar (.ics)":                              "Als Kalender speichern (.ics)",
+	"One event per timesheet, to import in a calendar app": "Ein Termin pro Zeiteintrag, zum Import in eine Kalender-App",
+	"Actions":                            "Aktionen",
+	"Run the commands of custom_actions": "Die Befehle aus custom_actions ausführen",
+	"Start at…":                          "Gestartet um…",
+	"Stop at…":                           "Stoppen um…",
+	"Other time…":                        "Andere Uhrzeit…",
+	"%d minutes ago":                     "Vor %d Minuten",
+	"Top of the hour":                    "Zur vollen Stunde",
+	"Open dashboard":                     "Dashboard öffnen",
+	"Show the timers in the browser":     "Die Timer im Browser anzeigen",
+	"Syncing…":                           "Synchronisiere…",
+	"Last sync %s — OK":                  "Letzte Synchronisierung %s — OK",
+	"Sync failed: %s":                    "Synchronisierung fehlgeschlagen: %s",
+	"Showing data from %s":               "Daten von %s",
+	"Monday":                             "Montag",
+	"Tuesday":                            "Dienstag",
+	"Wednesday":                          "Mittwoch",
+	"Thursday":                           "Donnerstag",
+	"Friday":                             "Freitag",
+	"Saturday":                           "Samstag",
+	"Sunday":                             "Sonntag",
+
+	// dialogs
+	"API token for %s":                "API-Token für %s",
+	"Description for %s":              "Beschreibung für %s",
+	"Search a project or an activity": "Ein Projekt oder eine Tätigkeit suchen",
+	"Start a task for %q":             "Eine Aufgabe für %q starten",
+	"You have been idle since %s while tracking %s.\nKeep the idle time?": "Sie sind seit %s inaktiv, während %s läuft.\nDie inaktive Zeit behalten?",
+	"%s is still running (%s).\nStop it before quitting?":                 "%s läuft noch (%s).\nVor dem Beenden stoppen?",
+	"Stop %s at (09:30, or 10m for 10 minutes ago)":                       "%s stoppen um (09:30, oder 10m für vor 10 Minuten)",
+	"Start %s at (09:30, or 10m for 10 minutes ago)":                      "%s starten um (09:30, oder 10m für vor 10 Minuten)",
+
+	// notifications
+	"%d minutes, %s stopped after cycle %d": "%d Minuten, %s nach Zyklus %d gestoppt",
+	"%d running task(s) stopped":            "%d laufende Aufgabe(n) gestoppt",
+	"%s is %s (%s)":                         "%s ist %s (%s)",
+	"%s is still running (%s)":              "%s läuft noch (%s)",
+	"%s restarted, break at %s":             "%s neu gestartet, Pause um %s",
+	"%s stopped at %s":                      "%s um %s gestoppt",
+	"%s, as %s":                             "%s, als %s",
+	"%s, break at %s":                       "%s, Pause um %s",
+	"Authentication failed":                 "Anmeldung abgelehnt",
+	"Back to work":                          "Zurück an die Arbeit",
+	"Changing the autostart failed":         "Ändern des Autostarts fehlgeschlagen",
+	"Config reloaded":                       "Konfiguration neu geladen",
+	"Discarding the idle time failed":       "Verwerfen der inaktiven Zeit fehlgeschlagen",
+	"Export failed":                         "Export fehlgeschlagen",
+	"Favourites unavailable":                "Favoriten nicht verfügbar",
+	"Forgotten timer?":                      "Timer vergessen?",
+	"Hotkey unavailable":                    "Tastenkürzel nicht verfügbar",
+	"Idle detection, hotkeys, the language, the custom actions and the profile menu change after a restart": "Inaktivitätserkennung, Tastenkürzel, Sprache, eigene Aktionen und Profilmenü ändern sich nach einem Neustart",
+	"Kimai reachable again":                       "Kimai wieder erreichbar",
+	"Kimai unreachable":                           "Kimai nicht erreichbar",
+	"Kimai version not supported":                 "Kimai-Version nicht unterstützt",
+	"Login failed":                                "Anmeldung fehlgeschlagen",
+	"No dialog available, run qckm login instead": "Kein Dialog verfügbar, stattdessen qckm login ausführen",
+	"No project or activity matches":              "Kein Projekt und keine Tätigkeit passt",
+	"No project to pick":                          "Kein Projekt zur Auswahl",
+	"Nothing to restart":                          "Nichts neu zu starten",
+	"Opening Kimai failed":                        "Öffnen von Kimai fehlgeschlagen",
+	"Pomodoro started":                            "Pomodoro gestartet",
+	"Pomodoro stopped":                            "Pomodoro gestoppt",
+	"Queued action failed":                        "Aktion aus der Warteschlange fehlgeschlagen",
+	"Reloading the config failed":                 "Neuladen der Konfiguration fehlgeschlagen",
+	"Remove the token from the config file, it takes precedence": "Das Token aus der Konfigurationsdatei entfernen, es hat Vorrang",
+	"Restarting the task failed":                                 "Neustart der Aufgabe fehlgeschlagen",
+	"Resuming the task failed":                                   "Fortsetzen der Aufgabe fehlgeschlagen",
+	"Saving favourites failed":                                   "Speichern der Favoriten fehlgeschlagen",
+	"Setting the description failed":                             "Setzen der Beschreibung fehlgeschlagen",
+	"Starting the task failed":                                   "Starten der Aufgabe fehlgeschlagen",
+	"Stopping the idle task failed":                              "Stoppen der inaktiven Aufgabe fehlgeschlagen",
+	"Stopping the task failed":                                   "Stoppen der Aufgabe fehlgeschlagen",
+	"Switching profile failed":                                   "Profilwechsel fehlgeschlagen",
+	"Task paused":                                                "Aufgabe pausiert",
+	"Task restart failed":                                        "Neustart der Aufgabe fehlgeschlagen",
+	"Task resumed":                                               "Aufgabe fortgesetzt",
+	"Task started":                                               "Aufgabe gestartet",
+	"Task stop failed":                                           "Stoppen der Aufgabe fehlgeschlagen",
+	"Task stopped":                                               "Aufgabe gestoppt",
+	"Task stopped while idle":                                    "Aufgabe während der Inaktivität gestoppt",
+	"Tasks stopped":                                              "Aufgaben gestoppt",
+	"The API token was rejected, use \"Log in again…\" in the menu":   "Das API-Token wurde abgelehnt, im Menü „erneut anmelden…“ verwenden",
+	"The favourites file could not be loaded":                         "Die Favoritendatei konnte nicht geladen werden",
+	"The new token is only used until qckm quits: %s":                 "Das neue Token gilt nur bis zum Beenden von qckm: %s",
+	"The projects have not been fetched yet":                          "Die Projekte wurden noch nicht abgerufen",
+	"The restart will be sent once the server is back":                "Der Neustart wird gesendet, sobald der Server wieder erreichbar ist",
+	"The stop will be sent once the server is back":                   "Das Stoppen wird gesendet, sobald der Server wieder erreichbar ist",
+	"There is no recent task":                                         "Es gibt keine letzte Aufgabe",
+	"Time for a break":                                                "Zeit für eine Pause",
+	"Timesheets copied":                                               "Zeiteinträge kopiert",
+	"Timesheets exported":                                             "Zeiteinträge exportiert",
+	"Token not stored":                                                "Token nicht gespeichert",
+	"Token stored in the keyring":                                     "Token im Schlüsselbund gespeichert",
+	"qckm quit":                                                       "qckm beendet",
+	"Team view unavailable":                                           "Teamansicht nicht verfügbar",
+	"The API token is not allowed to see the other users' timesheets": "Das API-Token darf die Zeiteinträge anderer Benutzer nicht sehen",
+	"%s — %s, started %s":                                             "%s — %s, gestartet um %s",
+	"Copied to the clipboard":                                         "In die Zwischenablage kopiert",
+	"Copying failed":                                                  "Kopieren fehlgeschlagen",
+	"Suggested task":                                                  "Vorgeschlagene Aufgabe",
+	"[%s] %s, from %s %q":                                             "[%s] %s, laut %s %q",
+	"branch":                                                          "Branch",
+	"window":                                                          "Fenster",
+	"%s failed":                                                       "%s fehlgeschlagen",
+	"Daily summary unavailable":                                       "Tageszusammenfassung nicht verfügbar",
+	"Nothing tracked today":                                           "Heute nichts erfasst",
+	"%s still running (%s)":                                           "%s läuft noch (%s)",
+	"%s untracked between the tasks":                                  "%s nicht erfasst zwischen den Aufgaben",
+	"%q is not a time like 09:30 or a duration ago like 10m":          "%q ist weder eine Uhrzeit wie 09:30 noch eine Dauer wie 10m",
+	"%s is in the future":                                             "%s liegt in der Zukunft",
+	"No dialog available":                                             "Kein Dialog verfügbar",
+	"Invalid time":                                                    "Ungültige Uhrzeit",
+	"%s started at %s":                                                "%s um %s gestartet",
+	"Changing the start time failed":                                  "Ändern der Startzeit fehlgeschlagen",
+	"Start time changed":                                              "Startzeit geändert",
+	"Dashboard unavailable":                                           "Dashboard nicht verfügbar",
+	"Set dashboard.port in the config file":                           "dashboard.port in der Konfigurationsdatei setzen",
+	"Opening the dashboard failed":                                    "Öffnen des Dashboards fehlgeschlagen",
+	"qckm is set up":                                                  "qckm ist eingerichtet",
+	"Config written to %s":                                            "Konfiguration in %s geschrieben",
+	"Checking the server failed":                                      "Prüfen des Servers fehlgeschlagen",
+	"running for more than %s":                                        "seit mehr als %s aktiv",
+	"still running after %s":                                          "nach %s noch aktiv",
+}
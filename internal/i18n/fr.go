@@ -0,0 +1,193 @@
+package i18n
+
+var fr = map[string]string{
+	// menu
+	"Active":     "En cours",
+	"Active: %s": "En cours : %s",
+	"Authentication failed — log in again…": "Authentification refusée — se reconnecter…",
+	"Autostart at login":                    "Lancer à l'ouverture de session",
+	"Copy to clipboard":                     "Copier dans le presse-papiers",
+	"Edit description…":                     "Modifier la description…",
+	"Enter a new API token":                 "Saisir un nouveau jeton d'API",
+	"Export timesheets as %s":               "Exporter les feuilles de temps en %s",
+	"Export…":                               "Exporter…",
+	"Favourites":                            "Favoris",
+	"Last week":                             "Semaine dernière",
+	"Offline":                               "Hors ligne",
+	"Offline — %d queued action(s)":         "Hors ligne — %d action(s) en attente",
+	"Open in Kimai":                         "Ouvrir dans Kimai",
+	"Open the Kimai web interface":          "Ouvrir l'interface web de Kimai",
+	"Other":                                 "Autres",
+	"Pause":                                 "Pause",
+	"Pin to favourites":                     "Ajouter aux favoris",
+	"Quit":                                  "Quitter",
+	"Quit the whole app":                    "Quitter l'application",
+	"Read the config file again":            "Relire le fichier de configuration",
+	"Recent":                                "Récents",
+	"Refresh":                               "Actualiser",
+	"Refresh the menu":                      "Actualiser le menu",
+	"Reload config":                         "Recharger la configuration",
+	"Resume %s":                             "Reprendre %s",
+	"Save to %s":                            "Enregistrer dans %s",
+	"Search all projects…":                  "Chercher dans tous les projets…",
+	"Start a new task":                      "Démarrer une nouvelle tâche",
+	"Start a pinned task":                   "Démarrer une tâche favorite",
+	"Start a task on any project, filtered by name": "Démarrer une tâche sur n'importe quel projet, filtré par nom",
+	"Start new…":                        "Nouvelle tâche…",
+	"Start pomodoro":                    "Démarrer un pomodoro",
+	"Start qckm when you log in":        "Démarrer qckm à l'ouverture de session",
+	"Stop":                              "Arrêter",
+	"Stop all (%d)":                     "Tout arrêter (%d)",
+	"Stop pomodoro (%s)":                "Arrêter le pomodoro (%s)",
+	"Switch profile":                    "Changer de profil",
+	"The Kimai server can't be reached": "Le serveur Kimai est injoignable",
+	"This month":                        "Ce mois-ci",
+	"This week":                         "Cette semaine",
+	"This week: %s":                     "Cette semaine : %s",
+	"Time tracked this week":            "Temps saisi cette semaine",
+	"Time tracked today":                "Temps saisi aujourd'hui",
+	"Timesheets":                        "Feuilles de temps",
+	"Today":                             "Aujourd'hui",
+	"Today: %s":                         "Aujourd'hui : %s",
+	"Unpin from favourites":             "Retirer des favoris",
+	"Use another Kimai instance":        "Utiliser une autre instance Kimai",
+	"(offline)":                         "(hors ligne)",
+	"offline":                           "hors ligne",
+	"Team":                              "Équipe",
+	"Team (%d)":                         "Équipe (%d)",
+	"Running timers of all the users":   "Chronomètres en cours de tous les utilisateurs",
+	"Nobody is tracking time":           "Personne ne saisit de temps",
+	"Copy":                              "Copier",
+	"Copy a task summary, timesheet ID or link": "Copier le résumé, l'ID ou le lien d'une tâche",
+	"Summary":                    "Résumé",
+	"Summary of the active task": "Résumé de la tâche en cours",
+	"Timesheet ID (%d)":          "ID de la feuille de temps (%d)",
+	"Link":                       "Lien",
+	"Start suggested":            "Démarrer la suggestion",
+	"Start the task matching the git branch or window":     "Démarrer la tâche correspondant à la branche git ou à la fenêtre",
+	"Start suggested: [%s] %s":                             "Démarrer la suggestion : [%s] %s",
+	"Save as calendar (.ics)":                              "Enregistrer en calendrier (.ics)",
+	"One event per timesheet, to import in a calendar app": "Un événement par feuille de temps, à importer dans un agenda",
+	"Actions":                            "Actions",
+	"Run the commands of custom_actions": "Lancer les commandes de custom_actions",
+	"Start at…":                          "Démarrée à…",
+	"Stop at…":                           "Arrêter à…",
+	"Other time…":                        "Autre heure…",
+	"%d minutes ago":                     "Il y a %d minutes",
+	"Top of the hour":                    "Début de l'heure",
+	"Open dashboard":                     "Ouvrir le tableau de bord",
+	"Show the timers in the browser":     "Afficher les chronomètres dans le navigateur",
+	"Syncing…":                           "Synchronisation…",
+	"Last sync %s — OK":                  "Dernière synchro %s — OK",
+	"Sync failed: %s":                    "Échec de la synchro : %s",
+	"Showing data from %s":               "Données de %s",
+	"Monday":                             "Lundi",
+	"Tuesday":                            "Mardi",
+	"Wednesday":                          "Mercredi",
+	"Thursday":                           "Jeudi",
+	"Friday":                             "Vendredi",
+	"Saturday":                           "Samedi",
+	"Sunday":                             "Dimanche",
+
+	// dialogs
+	"API token for %s":                "Jeton d'API de %s",
+	"Description for %s":              "Description de %s",
+	"Search a project or an activity": "Chercher un projet ou une activité",
+	"Start a task for %q":             "Démarrer une tâche pour %q",
+	"You have been idle since %s while tracking %s.\nKeep the idle time?": "Vous êtes inactif depuis %s pendant le suivi de %s.\nConserver le temps d'inactivité ?",
+	"%s is still running (%s).\nStop it before quitting?":                 "%s est toujours en cours (%s).\nL'arrêter avant de quitter ?",
+	"Stop %s at (09:30, or 10m for 10 minutes ago)":                       "Arrêter %s à (09:30, ou 10m pour il y a 10 minutes)",
+	"Start %s at (09:30, or 10m for 10 minutes ago)":                      "Démarrer %s à (09:30, ou 10m pour il y a 10 minutes)",
+
+	// notifications
+	"%d minutes, %s stopped after cycle %d": "%d minutes, %s arrêté après le cycle %d",
+	"%d running task(s) stopped":            "%d tâche(s) en cours arrêtée(s)",
+	"%s is %s (%s)":                         "%s est %s (%s)",
+	"%s is still running (%s)":              "%s est toujours en cours (%s)",
+	"%s restarted, break at %s":             "%s redémarrée, pause à %s",
+	"%s stopped at %s":                      "%s arrêtée à %s",
+	"%s, as %s":                             "%s, en %s",
+	"%s, break at %s":                       "%s, pause à %s",
+	"Authentication failed":                 "Authentification refusée",
+	"Back to work":                          "Au travail",
+	"Changing the autostart failed":         "Échec de la modification du lancement automatique",
+	"Config reloaded":                       "Configuration rechargée",
+	"Discarding the idle time failed":       "Échec de la suppression du temps d'inactivité",
+	"Export failed":                         "Échec de l'export",
+	"Favourites unavailable":                "Favoris indisponibles",
+	"Forgotten timer?":                      "Chronomètre oublié ?",
+	"Hotkey unavailable":                    "Raccourci indisponible",
+	"Idle detection, hotkeys, the language, the custom actions and the profile menu change after a restart": "La détection d'inactivité, les raccourcis, la langue, les actions personnalisées et le menu des profils changent après un redémarrage",
+	"Kimai reachable again":                       "Kimai de nouveau joignable",
+	"Kimai unreachable":                           "Kimai injoignable",
+	"Kimai version not supported":                 "Version de Kimai non prise en charge",
+	"Login failed":                                "Échec de la connexion",
+	"No dialog available, run qckm login instead": "Aucune boîte de dialogue disponible, lancez plutôt qckm login",
+	"No project or activity matches":              "Aucun projet ni aucune activité ne correspond",
+	"No project to pick":                          "Aucun projet à choisir",
+	"Nothing to restart":                          "Rien à redémarrer",
+	"Opening Kimai failed":                        "Échec de l'ouverture de Kimai",
+	"Pomodoro started":                            "Pomodoro démarré",
+	"Pomodoro stopped":                            "Pomodoro arrêté",
+	"Queued action failed":                        "Échec d'une action en attente",
+	"Reloading the config failed":                 "Échec du rechargement de la configuration",
+	"Remove the token from the config file, it takes precedence": "Retirez le jeton du fichier de configuration, il est prioritaire",
+	"Restarting the task failed":                                 "Échec du redémarrage de la tâche",
+	"Resuming the task failed":                                   "Échec de la reprise de la tâche",
+	"Saving favourites failed":                                   "Échec de l'enregistrement des favoris",
+	"Setting the description failed":                             "Échec de la modification de la description",
+	"Starting the task failed":                                   "Échec du démarrage de la tâche",
+	"Stopping the idle task failed":                              "Échec de l'arrêt de la tâche inactive",
+	"Stopping the task failed":                                   "Échec de l'arrêt de la tâche",
+	"Switching profile failed":                                   "Échec du changement de profil",
+	"Task paused":                                                "Tâche en pause",
+	"Task restart failed":                                        "Échec du redémarrage de la tâche",
+	"Task resumed":                                               "Tâche reprise",
+	"Task started":                                               "Tâche démarrée",
+	"Task stop failed":                                           "Échec de l'arrêt de la tâche",
+	"Task stopped":                                               "Tâche arrêtée",
+	"Task stopped while idle":                                    "Tâche arrêtée pendant l'inactivité",
+	"Tasks stopped":                                              "Tâches arrêtées",
+	"The API token was rejected, use \"Log in again…\" in the menu":   "Le jeton d'API a été refusé, utilisez « se reconnecter… » dans le menu",
+	"The favourites file could not be loaded":                         "Le fichier des favoris n'a pas pu être chargé",
+	"The new token is only used until qckm quits: %s":                 "Le nouveau jeton ne sert que jusqu'à la fermeture de qckm : %s",
+	"The projects have not been fetched yet":                          "Les projets n'ont pas encore été récupérés",
+	"The restart will be sent once the server is back":                "Le redémarrage sera envoyé au retour du serveur",
+	"The stop will be sent once the server is back":                   "L'arrêt sera envoyé au retour du serveur",
+	"There is no recent task":                                         "Il n'y a aucune tâche récente",
+	"Time for a break":                                                "C'est l'heure de la pause",
+	"Timesheets copied":                                               "Feuilles de temps copiées",
+	"Timesheets exported":                                             "Feuilles de temps exportées",
+	"Token not stored":                                                "Jeton non enregistré",
+	"Token stored in the keyring":                                     "Jeton enregistré dans le trousseau",
+	"qckm quit":                                                       "qckm fermé",
+	"Team view unavailable":                                           "Vue équipe indisponible",
+	"The API token is not allowed to see the other users' timesheets": "Le jeton d'API ne permet pas de voir les feuilles de temps des autres utilisateurs",
+	"%s — %s, started %s":                                             "%s — %s, démarrée à %s",
+	"Copied to the clipboard":                                         "Copié dans le presse-papiers",
+	"Copying failed":                                                  "Échec de la copie",
+	"Suggested task":                                                  "Tâche suggérée",
+	"[%s] %s, from %s %q":                                             "[%s] %s, d'après %s %q",
+	"branch":                                                          "la branche",
+	"window":                                                          "la fenêtre",
+	"%s failed":                                                       "Échec de %s",
+	"Daily summary unavailable":                                       "Résumé du jour indisponible",
+	"Nothing tracked today":                                           "Rien de saisi aujourd'hui",
+	"%s still running (%s)":                                           "%s toujours en cours (%s)",
+	"%s untracked between the tasks":                                  "%s non saisies entre les tâches",
+	"%q is not a time like 09:30 or a duration ago like 10m":          "%q n'est ni une heure comme 09:30 ni une durée comme 10m",
+	"%s is in the future":                                             "%s est dans le futur",
+	"No dialog available":                                             "Aucune boîte de dialogue disponible",
+	"Invalid time":                                                    "Heure invalide",
+	"%s started at %s":                                                "%s démarrée à %s",
+	"Changing the start time failed":                                  "Échec de la modification de l'heure de début",
+	"Start time changed":                                              "Heure de début modifiée",
+	"Dashboard unavailable":                                           "Tableau de bord indisponible",
+	"Set dashboard.port in the config file":                           "Renseignez dashboard.port dans le fichier de configuration",
+	"Opening the dashboard failed":                                    "Échec de l'ouverture du tableau de bord",
+	"qckm is set up":                                                  "qckm est configuré",
+	"Config written to %s":                                            "Configuration écrite dans %s",
+	"Checking the server failed":                                      "Échec de la vérification du serveur",
+	"running for more than %s":                                        "en cours depuis plus de %s",
+	"still running after %s":                                          "toujours en cours après %s",
+}
@@ -0,0 +1,92 @@
+// Package i18n translates the menu labels, dialogs and notifications. The
+// messages are keyed by their English text, used when no translation exists.
+package i18n
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+)
+
+const (
+	AUTO    = "auto"
+	ENGLISH = "en"
+	FRENCH  = "fr"
+	GERMAN  = "de"
+)
+
+var LANGUAGES = []string{ENGLISH, FRENCH, GERMAN}
+
+var catalogues = map[string]map[string]string{
+	FRENCH: fr,
+	GERMAN: de,
+}
+
+var (
+	mu       sync.RWMutex
+	language = ENGLISH
+)
+
+// SetLanguage selects one of LANGUAGES, or the detected one for AUTO or "".
+func SetLanguage(lang string) error {
+	switch lang {
+	case "", AUTO:
+		lang = Detect()
+	case ENGLISH, FRENCH, GERMAN:
+	default:
+		return fmt.Errorf("invalid language %q, expected %q or one of %v", lang, AUTO, LANGUAGES)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	language = lang
+	return nil
+}
+
+func Language() string {
+	mu.RLock()
+	defer mu.RUnlock()
+	return language
+}
+
+// Detect returns the language of the user session among LANGUAGES, from
+// $LC_ALL, $LC_MESSAGES, $LANG, then the OS settings, English otherwise.
+func Detect() string {
+	for _, name := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		if value := os.Getenv(name); value != "" {
+			return supported(value)
+		}
+	}
+	return supported(systemLocale())
+}
+
+// supported turns a locale like fr_FR.UTF-8 or de-DE into one of LANGUAGES.
+func supported(locale string) string {
+	parts := strings.FieldsFunc(locale, func(r rune) bool {
+		return r == '_' || r == '-' || r == '.' || r == '@'
+	})
+	if len(parts) == 0 {
+		return ENGLISH
+	}
+	lang := strings.ToLower(parts[0])
+	if _, ok := catalogues[lang]; ok {
+		return lang
+	}
+	return ENGLISH
+}
+
+// T translates message, then formats it with args if any.
+func T(message string, args ...interface{}) string {
+	mu.RLock()
+	catalogue := catalogues[language]
+	mu.RUnlock()
+
+	if translated, ok := catalogue[message]; ok {
+		message = translated
+	}
+	if len(args) == 0 {
+		return message
+	}
+	return fmt.Sprintf(message, args...)
+}
@@ -0,0 +1,16 @@
+package i18n
+
+import (
+	"os/exec"
+	"strings"
+)
+
+// systemLocale is the region setting of macOS, e.g. fr_FR, apps started from
+// the Finder having no $LANG.
+func systemLocale() string {
+	out, err := exec.Command("defaults", "read", "-g", "AppleLocale").Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
@@ -0,0 +1,8 @@
+//go:build !darwin && !windows
+
+package i18n
+
+// systemLocale has nothing to add to the environment variables.
+func systemLocale() string {
+	return ""
+}
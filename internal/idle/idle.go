@@ -0,0 +1,14 @@
+// Package idle reports for how long the user has not touched keyboard or mouse.
+package idle
+
+import (
+	"errors"
+	"time"
+)
+
+var ErrUnsupported = errors.New("idle time detection is not supported on this desktop")
+
+// Duration returns the time elapsed since the last user input.
+func Duration() (time.Duration, error) {
+	return duration()
+}
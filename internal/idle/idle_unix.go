@@ -0,0 +1,59 @@
+//go:build !windows
+
+package idle
+
+import (
+	"bytes"
+	"os/exec"
+	"regexp"
+	"runtime"
+	"strconv"
+	"time"
+)
+
+var (
+	// "HIDIdleTime" = 1234567890 (nanoseconds)
+	ioregIdle = regexp.MustCompile(`"HIDIdleTime" = (\d+)`)
+	// (uint64 12345,) (milliseconds)
+	mutterIdle = regexp.MustCompile(`uint64 (\d+)`)
+)
+
+func duration() (time.Duration, error) {
+	if runtime.GOOS == "darwin" {
+		out, err := exec.Command("ioreg", "-c", "IOHIDSystem").Output()
+		if err != nil {
+			return 0, err
+		}
+		return parse(ioregIdle, out, time.Nanosecond)
+	}
+
+	// X11 screensaver extension
+	if out, err := exec.Command("xprintidle").Output(); err == nil {
+		ms, err := strconv.ParseInt(string(bytes.TrimSpace(out)), 10, 64)
+		if err == nil {
+			return time.Duration(ms) * time.Millisecond, nil
+		}
+	}
+
+	// GNOME, including Wayland sessions
+	out, err := exec.Command("gdbus", "call", "--session",
+		"--dest", "org.gnome.Mutter.IdleMonitor",
+		"--object-path", "/org/gnome/Mutter/IdleMonitor/Core",
+		"--method", "org.gnome.Mutter.IdleMonitor.GetIdletime").Output()
+	if err != nil {
+		return 0, ErrUnsupported
+	}
+	return parse(mutterIdle, out, time.Millisecond)
+}
+
+func parse(re *regexp.Regexp, out []byte, unit time.Duration) (time.Duration, error) {
+	match := re.FindSubmatch(out)
+	if match == nil {
+		return 0, ErrUnsupported
+	}
+	value, err := strconv.ParseInt(string(match[1]), 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(value) * unit, nil
+}
@@ -0,0 +1,151 @@
+// Package ipc lets other processes control the running tray over a Unix
+// socket, with one JSON request and one JSON response per line.
+package ipc
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	STATUS       = "status"
+	STOP         = "stop"
+	RESTART_LAST = "restart_last"
+	REFRESH      = "refresh"
+
+	CALL_TIMEOUT = 5 * time.Second
+)
+
+// ErrInUse is returned by Listen when another process answers on the socket.
+var ErrInUse = errors.New("in use by another qckm")
+
+type Request struct {
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params,omitempty"`
+}
+
+type Response struct {
+	Result interface{} `json:"result,omitempty"`
+	Error  string      `json:"error,omitempty"`
+}
+
+// Handler runs a request method, unknown methods should return an error.
+type Handler func(method string, params json.RawMessage) (interface{}, error)
+
+// Server accepts the connections of the socket until Close.
+type Server struct {
+	path     string
+	listener net.Listener
+	handler  Handler
+}
+
+// Listen creates the socket at path. A socket left behind by a process that
+// is gone is replaced, one still answering means another tray is running.
+func Listen(path string, handler Handler) (*Server, error) {
+	if conn, err := net.DialTimeout("unix", path, time.Second); err == nil {
+		conn.Close()
+		return nil, fmt.Errorf("%s: %w", path, ErrInUse)
+	}
+	os.Remove(path)
+
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, err
+	}
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.Chmod(path, 0600); err != nil {
+		listener.Close()
+		return nil, err
+	}
+
+	s := &Server{path: path, listener: listener, handler: handler}
+	go s.serve()
+	return s, nil
+}
+
+func (s *Server) Close() error {
+	err := s.listener.Close()
+	os.Remove(s.path)
+	return err
+}
+
+func (s *Server) serve() {
+	for {
+		conn, err := s.listener.Accept()
+		if errors.Is(err, net.ErrClosed) {
+			return
+		}
+		if err != nil {
+			slog.Warn("control socket accept failed", "err", err)
+			continue
+		}
+		go s.handle(conn)
+	}
+}
+
+func (s *Server) handle(conn net.Conn) {
+	defer conn.Close()
+
+	scanner := bufio.NewScanner(conn)
+	encoder := json.NewEncoder(conn)
+	for scanner.Scan() {
+		var req Request
+		var resp Response
+		if err := json.Unmarshal(scanner.Bytes(), &req); err != nil {
+			resp.Error = "invalid request: " + err.Error()
+		} else if result, err := s.handler(req.Method, req.Params); err != nil {
+			resp.Error = err.Error()
+		} else {
+			resp.Result = result
+		}
+
+		if err := encoder.Encode(resp); err != nil {
+			return
+		}
+	}
+}
+
+// Call sends a request to the socket at path and decodes the result into out,
+// unless out is nil.
+func Call(path string, method string, params interface{}, out interface{}) error {
+	conn, err := net.DialTimeout("unix", path, CALL_TIMEOUT)
+	if err != nil {
+		return fmt.Errorf("no running qckm tray: %w", err)
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(CALL_TIMEOUT))
+
+	req := Request{Method: method}
+	if params != nil {
+		if req.Params, err = json.Marshal(params); err != nil {
+			return err
+		}
+	}
+	if err := json.NewEncoder(conn).Encode(req); err != nil {
+		return err
+	}
+
+	var resp struct {
+		Result json.RawMessage `json:"result"`
+		Error  string          `json:"error"`
+	}
+	if err := json.NewDecoder(conn).Decode(&resp); err != nil {
+		return err
+	}
+	if resp.Error != "" {
+		return errors.New(resp.Error)
+	}
+	if out == nil || resp.Result == nil {
+		return nil
+	}
+	return json.Unmarshal(resp.Result, out)
+}
@@ -0,0 +1,40 @@
+package kimai
+
+import (
+	"context"
+	"net/http"
+)
+
+const (
+	// AUTH_AUTO picks AUTH_BEARER on Kimai 2 servers, AUTH_LEGACY otherwise.
+	AUTH_AUTO = "auto"
+	// AUTH_LEGACY sends the X-AUTH-USER and X-AUTH-TOKEN headers of Kimai 1.
+	AUTH_LEGACY = "legacy"
+	// AUTH_BEARER sends the Kimai 2 API token as Authorization: Bearer.
+	AUTH_BEARER = "bearer"
+)
+
+func (c *Client) setAuth(req *http.Request, mode string) {
+	if mode == AUTH_BEARER {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+		return
+	}
+	req.Header.Set("X-AUTH-USER", c.username)
+	req.Header.Set("X-AUTH-TOKEN", c.token)
+}
+
+// AuthMode returns the header scheme in use, detecting it on first use in
+// auto mode, see Compat. AUTH_LEGACY is assumed until the detection succeeds.
+func (c *Client) AuthMode(ctx context.Context) (string, error) {
+	compat, err := c.Compat(ctx)
+	if c.authMode != AUTH_AUTO {
+		return c.authMode, err
+	}
+	if err != nil && !IsUnsupportedVersion(err) {
+		return AUTH_LEGACY, err
+	}
+	if compat.Bearer {
+		return AUTH_BEARER, err
+	}
+	return AUTH_LEGACY, err
+}
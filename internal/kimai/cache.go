@@ -0,0 +1,63 @@
+package kimai
+
+import (
+	"net/http"
+	"sync"
+)
+
+// responseCache keeps the last GET responses carrying an ETag or a
+// Last-Modified header, to send conditional requests and reuse the body when
+// the server answers 304 Not Modified.
+type responseCache struct {
+	mu      sync.Mutex
+	entries map[string]cachedResponse
+}
+
+type cachedResponse struct {
+	etag         string
+	lastModified string
+	body         []byte
+}
+
+// prepare adds the validators of the cached response to req.
+func (c *responseCache) prepare(req *http.Request, endpoint string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[endpoint]
+	if !ok {
+		return
+	}
+	if entry.etag != "" {
+		req.Header.Set("If-None-Match", entry.etag)
+	}
+	if entry.lastModified != "" {
+		req.Header.Set("If-Modified-Since", entry.lastModified)
+	}
+}
+
+// lookup returns the cached body of endpoint, if any.
+func (c *responseCache) lookup(endpoint string) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[endpoint]
+	return entry.body, ok
+}
+
+// store keeps body if the response can be validated later.
+func (c *responseCache) store(endpoint string, header http.Header, body []byte) {
+	entry := cachedResponse{etag: header.Get("ETag"), lastModified: header.Get("Last-Modified"), body: body}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if entry.etag == "" && entry.lastModified == "" {
+		delete(c.entries, endpoint)
+		return
+	}
+	if c.entries == nil {
+		c.entries = make(map[string]cachedResponse)
+	}
+	c.entries[endpoint] = entry
+}
@@ -0,0 +1,101 @@
+package kimai
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+const (
+	CUSTOMERS_ENDPOINT  = "customers?visible=1"
+	PROJECTS_ENDPOINT   = "projects?visible=1"
+	ACTIVITIES_ENDPOINT = "activities?visible=1"
+	START_ENDPOINT      = "timesheets?full=true"
+)
+
+func (c *Client) FetchCustomers(ctx context.Context) ([]Customer, error) {
+	var customers []Customer
+	if err := c.do(ctx, http.MethodGet, CUSTOMERS_ENDPOINT, nil, &customers); err != nil {
+		return nil, err
+	}
+	return customers, nil
+}
+
+func (c *Client) FetchProjects(ctx context.Context) ([]Project, error) {
+	var projects []Project
+	if err := c.do(ctx, http.MethodGet, PROJECTS_ENDPOINT, nil, &projects); err != nil {
+		return nil, err
+	}
+	return projects, nil
+}
+
+// FetchActivities returns all visible activities, global ones included.
+func (c *Client) FetchActivities(ctx context.Context) ([]Activity, error) {
+	// the collection only references the project by id
+	var entries []struct {
+		Id      int    `json:"id"`
+		Name    string `json:"name"`
+		Project *int   `json:"project"`
+	}
+	if err := c.do(ctx, http.MethodGet, ACTIVITIES_ENDPOINT, nil, &entries); err != nil {
+		return nil, err
+	}
+
+	activities := make([]Activity, 0, len(entries))
+	for _, entry := range entries {
+		activity := Activity{Id: entry.Id, Name: entry.Name}
+		if entry.Project != nil {
+			activity.ProjectId = *entry.Project
+		}
+		activities = append(activities, activity)
+	}
+	return activities, nil
+}
+
+// ActivitiesFor filters activities down to the ones usable with the given project.
+func ActivitiesFor(activities []Activity, projectId int) []Activity {
+	var res []Activity
+	for _, activity := range activities {
+		if activity.ProjectId == 0 || activity.ProjectId == projectId {
+			res = append(res, activity)
+		}
+	}
+	return res
+}
+
+// StartOptions are the optional fields of a new timesheet, left to the
+// server defaults when empty.
+type StartOptions struct {
+	Description string
+	Billable    *bool
+	FixedRate   *float64
+	HourlyRate  *float64
+}
+
+// StartTask creates a new running timesheet for the project and activity.
+func (c *Client) StartTask(ctx context.Context, projectId int, activityId int, opts StartOptions) (Task, error) {
+	payload := map[string]interface{}{
+		"project":  projectId,
+		"activity": activityId,
+		"begin":    time.Now().Format(DATETIME_FORMAT),
+	}
+	if opts.Description != "" {
+		payload["description"] = opts.Description
+	}
+	if compat, _ := c.Compat(ctx); opts.Billable != nil && compat.Billable {
+		payload["billable"] = *opts.Billable
+	}
+	if opts.FixedRate != nil {
+		payload["fixedRate"] = *opts.FixedRate
+	}
+	if opts.HourlyRate != nil {
+		payload["hourlyRate"] = *opts.HourlyRate
+	}
+
+	var task Task
+	err := c.do(ctx, http.MethodPost, START_ENDPOINT, payload, &task)
+	if err == nil {
+		task = c.fillMetaFields(ctx, task)
+	}
+	return task, err
+}
@@ -0,0 +1,376 @@
+package kimai
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	RECENT_ENDPOINT  = "timesheets/recent?size=%d"
+	ACTIVE_ENDPOINT  = "timesheets/active"
+	RESTART_ENDPOINT = "timesheets/%d/restart?full=true"
+	STOP_ENDPOINT    = "timesheets/%d/stop"
+	UPDATE_ENDPOINT  = "timesheets/%d?full=true"
+	META_ENDPOINT    = "timesheets/%d/meta"
+)
+
+// Options configures a Client.
+type Options struct {
+	// URL of the Kimai API. Both the API root (https://host/api) and the
+	// historical timesheets endpoint (https://host/api/timesheets/) are accepted.
+	URL      string
+	Username string
+	Token    string
+	// AuthMode is AUTH_AUTO (default), AUTH_LEGACY or AUTH_BEARER.
+	AuthMode string
+	// HTTPClient is used to perform requests. If nil a client with the given
+	// Timeout and TLS config is created.
+	HTTPClient *http.Client
+	// TLS configures the connections of the created client, the system
+	// defaults apply if nil.
+	TLS *tls.Config
+	// Proxy of the created client, with the credentials as user info.
+	// HTTP_PROXY, HTTPS_PROXY and NO_PROXY are honoured if nil.
+	Proxy *url.URL
+	// Timeout of a single request attempt, DEFAULT_TIMEOUT if 0.
+	Timeout time.Duration
+	// Retries of requests failing with a transient error, negative to disable.
+	Retries int
+	// Backoff is the delay before the first retry, doubled on each attempt.
+	// DEFAULT_BACKOFF if 0.
+	Backoff time.Duration
+	// Logger traces requests and responses at debug level, nothing is logged if nil.
+	Logger *slog.Logger
+	// RateLimit is the maximum number of requests per second, 0 for no limit.
+	// The requests are sent one at a time in any case.
+	RateLimit float64
+	// MetaFields are set on the started and restarted timesheets leaving
+	// them empty, by name, for instances requiring custom fields.
+	MetaFields map[string]string
+}
+
+const (
+	DEFAULT_TIMEOUT = 10 * time.Second
+	DEFAULT_BACKOFF = 500 * time.Millisecond
+)
+
+// Client talks to a single Kimai instance.
+type Client struct {
+	baseURL  string
+	username string
+	token    string
+	http     *http.Client
+	retries  int
+	backoff  time.Duration
+	logger   *slog.Logger
+
+	authMode   string
+	metaFields map[string]string
+
+	// compat is detected on first use, see Compat
+	compatMu  sync.Mutex
+	compat    *Compat
+	compatErr error
+
+	cache     responseCache
+	scheduler *scheduler
+}
+
+func New(opts Options) *Client {
+	httpClient := opts.HTTPClient
+	if httpClient == nil {
+		timeout := opts.Timeout
+		if timeout == 0 {
+			timeout = DEFAULT_TIMEOUT
+		}
+		httpClient = &http.Client{Timeout: timeout}
+		if opts.TLS != nil || opts.Proxy != nil {
+			transport := http.DefaultTransport.(*http.Transport).Clone()
+			transport.TLSClientConfig = opts.TLS
+			if opts.Proxy != nil {
+				transport.Proxy = http.ProxyURL(opts.Proxy)
+			}
+			httpClient.Transport = transport
+		}
+	}
+
+	backoff := opts.Backoff
+	if backoff == 0 {
+		backoff = DEFAULT_BACKOFF
+	}
+
+	logger := opts.Logger
+	if logger == nil {
+		logger = slog.New(slog.NewTextHandler(io.Discard, nil))
+	}
+
+	authMode := opts.AuthMode
+	if authMode == "" {
+		authMode = AUTH_AUTO
+	}
+
+	return &Client{
+		baseURL:  apiRoot(opts.URL),
+		authMode: authMode,
+		username: opts.Username,
+		token:    opts.Token,
+		http:     httpClient,
+		retries:  opts.Retries,
+		backoff:  backoff,
+		logger:   logger,
+
+		metaFields: opts.MetaFields,
+		scheduler:  newScheduler(opts.RateLimit),
+	}
+}
+
+// apiRoot turns the configured URL into the API root, with a trailing slash.
+func apiRoot(url string) string {
+	root := strings.TrimRight(url, "/")
+	root = strings.TrimSuffix(root, "/timesheets")
+	if !strings.HasSuffix(root, "/api") {
+		root += "/api"
+	}
+	return root + "/"
+}
+
+func (c *Client) newRequest(ctx context.Context, method string, endpoint string, body io.Reader) (*http.Request, error) {
+	mode, err := c.AuthMode(ctx)
+	if err != nil && (ctx.Err() != nil || IsUnsupportedVersion(err)) {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+endpoint, body)
+	if err != nil {
+		return nil, err
+	}
+
+	c.setAuth(req, mode)
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	return req, nil
+}
+
+// do performs the request, retrying transient failures, and decodes the JSON
+// response into out, unless out is nil. A GET identical to one in flight
+// waits for its response instead of being sent again.
+func (c *Client) do(ctx context.Context, method string, endpoint string, in interface{}, out interface{}) error {
+	var payload []byte
+	if in != nil {
+		var err error
+		if payload, err = json.Marshal(in); err != nil {
+			return err
+		}
+	}
+
+	var data []byte
+	var err error
+	if method == http.MethodGet {
+		data, err = c.scheduler.merge(endpoint, func() ([]byte, error) {
+			return c.retry(ctx, method, endpoint, nil)
+		})
+	} else {
+		data, err = c.retry(ctx, method, endpoint, payload)
+	}
+	if err != nil || out == nil {
+		return err
+	}
+	return json.Unmarshal(data, out)
+}
+
+// retry sends the request until it succeeds or fails for good.
+func (c *Client) retry(ctx context.Context, method string, endpoint string, payload []byte) ([]byte, error) {
+	for attempt := 0; ; attempt++ {
+		data, err := c.send(ctx, method, endpoint, payload)
+		if err == nil {
+			return data, nil
+		}
+
+		if attempt >= c.retries || !retryable(method, err) {
+			return nil, err
+		}
+		// a server asking to come back much later is better handled by the next refresh
+		delay := c.delay(attempt, err)
+		if delay > MAX_BACKOFF {
+			return nil, err
+		}
+		c.logger.Debug("retrying request", "method", method, "endpoint", endpoint, "attempt", attempt+1, "delay", delay, "err", err)
+		if err := sleep(ctx, delay); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// send performs a single request attempt and returns the response body.
+func (c *Client) send(ctx context.Context, method string, endpoint string, payload []byte) ([]byte, error) {
+	var body io.Reader
+	if payload != nil {
+		body = bytes.NewReader(payload)
+	}
+
+	req, err := c.newRequest(ctx, method, endpoint, body)
+	if err != nil {
+		return nil, err
+	}
+
+	if method == http.MethodGet {
+		c.cache.prepare(req, endpoint)
+	}
+
+	release, err := c.scheduler.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	c.logger.Debug("request", "method", method, "url", req.URL.String(), "body", string(payload))
+	start := time.Now()
+	res, err := c.http.Do(req)
+	if err != nil {
+		c.logger.Debug("request failed", "method", method, "url", req.URL.String(), "err", err)
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	data, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+	c.logger.Debug("response", "method", method, "url", req.URL.String(), "status", res.StatusCode,
+		"duration", time.Since(start), "body", string(data))
+
+	if res.StatusCode == http.StatusNotModified && method == http.MethodGet {
+		if cached, ok := c.cache.lookup(endpoint); ok {
+			return cached, nil
+		}
+	}
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		apiErr := &APIError{
+			Method:     method,
+			Endpoint:   endpoint,
+			StatusCode: res.StatusCode,
+			Status:     res.Status,
+			RetryAfter: parseRetryAfter(res.Header.Get("Retry-After"), time.Now()),
+		}
+		var payload struct {
+			Message string `json:"message"`
+		}
+		if json.Unmarshal(data, &payload) == nil {
+			apiErr.Message = payload.Message
+		}
+		return nil, apiErr
+	}
+
+	if method == http.MethodGet {
+		c.cache.store(endpoint, res.Header, data)
+	}
+	return data, nil
+}
+
+func (c *Client) StopTask(ctx context.Context, id int) error {
+	return c.do(ctx, http.MethodPatch, fmt.Sprintf(STOP_ENDPOINT, id), nil, nil)
+}
+
+// RestartTask starts a new timesheet from the given one and returns it.
+func (c *Client) RestartTask(ctx context.Context, id int) (Task, error) {
+	var task Task
+	err := c.do(ctx, http.MethodPatch, fmt.Sprintf(RESTART_ENDPOINT, id), nil, &task)
+	if err == nil {
+		task = c.fillMetaFields(ctx, task)
+	}
+	return task, err
+}
+
+// FetchActive returns the running timesheet, the first one if the server
+// allows several, or a NoActiveTaskError.
+func (c *Client) FetchActive(ctx context.Context) (Task, error) {
+	active, err := c.FetchActiveTasks(ctx)
+	if err != nil {
+		return Task{}, err
+	}
+
+	if len(active) == 0 {
+		return Task{}, NoActiveTaskError{}
+	}
+	return active[0], nil
+}
+
+// FetchActiveTasks returns all the running timesheets, none being no error.
+func (c *Client) FetchActiveTasks(ctx context.Context) ([]Task, error) {
+	var active []Task
+	if err := c.do(ctx, http.MethodGet, ACTIVE_ENDPOINT, nil, &active); err != nil {
+		return nil, err
+	}
+	return active, nil
+}
+
+// FetchRecent returns the size most recent timesheets, most recent first.
+func (c *Client) FetchRecent(ctx context.Context, size int) ([]Task, error) {
+	var recent []Task
+	if err := c.do(ctx, http.MethodGet, fmt.Sprintf(RECENT_ENDPOINT, size), nil, &recent); err != nil {
+		return nil, err
+	}
+	return recent, nil
+}
+
+// UpdateTask patches the given timesheet fields (description, end...).
+func (c *Client) UpdateTask(ctx context.Context, id int, fields map[string]interface{}) (Task, error) {
+	var task Task
+	err := c.do(ctx, http.MethodPatch, fmt.Sprintf(UPDATE_ENDPOINT, id), fields, &task)
+	return task, err
+}
+
+// SetMeta sets the value of a custom field of the timesheet.
+func (c *Client) SetMeta(ctx context.Context, id int, name string, value string) (Task, error) {
+	var task Task
+	err := c.do(ctx, http.MethodPatch, fmt.Sprintf(META_ENDPOINT, id), map[string]string{"name": name, "value": value}, &task)
+	return task, err
+}
+
+// fillMetaFields sets the configured custom fields the new task leaves
+// empty. The task runs anyway, so failures are only logged.
+func (c *Client) fillMetaFields(ctx context.Context, task Task) Task {
+	names := make([]string, 0, len(c.metaFields))
+	for name := range c.metaFields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if task.Meta(name) != "" {
+			continue
+		}
+		updated, err := c.SetMeta(ctx, task.Id, name, c.metaFields[name])
+		if err != nil {
+			c.logger.Warn("setting the custom field failed", "id", task.Id, "field", name, "err", err)
+			continue
+		}
+		task.MetaFields = updated.MetaFields
+	}
+	return task
+}
+
+// SetDescription replaces the description of the timesheet.
+func (c *Client) SetDescription(ctx context.Context, id int, description string) error {
+	_, err := c.UpdateTask(ctx, id, map[string]interface{}{"description": description})
+	return err
+}
+
+// StopTaskAt stops the timesheet with the given end time instead of now.
+func (c *Client) StopTaskAt(ctx context.Context, id int, end time.Time) error {
+	_, err := c.UpdateTask(ctx, id, map[string]interface{}{"end": end.Format(DATETIME_FORMAT)})
+	return err
+}
@@ -0,0 +1,140 @@
+package kimai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// MIN_VERSION_ID is the oldest Kimai supported, 1.10. Version ids are
+// major*10000 + minor*100 + patch.
+const MIN_VERSION_ID = 11000
+
+// BILLABLE_VERSION_ID is Kimai 1.15, which added the billable flag of the timesheets.
+const BILLABLE_VERSION_ID = 11500
+
+// Compat are the differences between Kimai versions the client adapts to,
+// detected from the version endpoint on first use. Endpoints and field
+// names are otherwise the same in 1.x and 2.x.
+type Compat struct {
+	Version Version
+	// Bearer is set on Kimai 2, which takes the API token as Authorization:
+	// Bearer rather than X-AUTH-USER/X-AUTH-TOKEN.
+	Bearer bool
+	// Billable is set when timesheets accept the billable field, older
+	// servers rejecting unknown fields.
+	Billable bool
+}
+
+func compatFor(version Version, bearer bool) Compat {
+	return Compat{
+		Version:  version,
+		Bearer:   bearer,
+		Billable: version.Id() >= BILLABLE_VERSION_ID,
+	}
+}
+
+// UnsupportedVersionError is returned by every request to a Kimai older than
+// MIN_VERSION_ID.
+type UnsupportedVersionError struct {
+	Version Version
+}
+
+func (e UnsupportedVersionError) Error() string {
+	return fmt.Sprintf("Kimai %s is not supported, qckm needs Kimai 1.10 or newer", e.Version.Version)
+}
+
+func IsUnsupportedVersion(err error) bool {
+	var e UnsupportedVersionError
+	return errors.As(err, &e)
+}
+
+// Compat returns the server differences, detecting them on first use. Once
+// detected an unsupported version fails every call.
+func (c *Client) Compat(ctx context.Context) (Compat, error) {
+	c.compatMu.Lock()
+	defer c.compatMu.Unlock()
+
+	if c.compat != nil {
+		return *c.compat, c.compatErr
+	}
+
+	compat, err := c.detectCompat(ctx)
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		if apiErr.StatusCode != http.StatusNotFound {
+			// refused credentials or a server error: this request goes on with
+			// the legacy headers and the detection is tried again on the next
+			return Compat{}, nil
+		}
+		// no version endpoint, a definitive answer
+		compat, err = Compat{}, nil
+	}
+	if err != nil {
+		// try again on the next request
+		return Compat{}, err
+	}
+	c.compat = &compat
+	if id := compat.Version.Id(); id > 0 && id < MIN_VERSION_ID {
+		c.compatErr = UnsupportedVersionError{Version: compat.Version}
+	}
+	c.logger.Debug("kimai detected", "version", compat.Version.Version, "bearer", compat.Bearer, "err", c.compatErr)
+	return compat, c.compatErr
+}
+
+// detectCompat asks the version, with a bearer token first in auto mode:
+// Kimai 2 accepts it, Kimai 1 ignores the header and refuses the
+// unauthenticated request, asked again with the legacy headers.
+func (c *Client) detectCompat(ctx context.Context) (Compat, error) {
+	if c.authMode != AUTH_LEGACY {
+		version, err := c.fetchVersionWith(ctx, AUTH_BEARER)
+		if err == nil && (version.Major() >= 2 || c.authMode == AUTH_BEARER) {
+			return compatFor(version, true), nil
+		}
+		if c.authMode == AUTH_BEARER {
+			return Compat{}, err
+		}
+		if ctx.Err() != nil {
+			return Compat{}, ctx.Err()
+		}
+	}
+
+	version, err := c.fetchVersionWith(ctx, AUTH_LEGACY)
+	if err != nil {
+		return Compat{}, err
+	}
+	return compatFor(version, false), nil
+}
+
+// fetchVersionWith asks the version with the given auth mode, outside of do
+// which depends on the detection.
+func (c *Client) fetchVersionWith(ctx context.Context, mode string) (Version, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+VERSION_ENDPOINT, nil)
+	if err != nil {
+		return Version{}, err
+	}
+	req.Header.Set("Accept", "application/json")
+	c.setAuth(req, mode)
+
+	release, err := c.scheduler.acquire(ctx)
+	if err != nil {
+		return Version{}, err
+	}
+	defer release()
+	res, err := c.http.Do(req)
+	if err != nil {
+		return Version{}, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return Version{}, &APIError{Method: http.MethodGet, Endpoint: VERSION_ENDPOINT, StatusCode: res.StatusCode, Status: res.Status}
+	}
+	var version Version
+	if err := json.NewDecoder(res.Body).Decode(&version); err != nil {
+		return Version{}, fmt.Errorf("invalid version answer: %w", err)
+	}
+	return version, nil
+}
@@ -0,0 +1,69 @@
+package kimai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+)
+
+// NoActiveTaskError is returned by FetchActive when no timesheet is running.
+type NoActiveTaskError struct{}
+
+func (e NoActiveTaskError) Error() string {
+	return "no active task"
+}
+
+// APIError is returned when the server answers with a non 2xx status code.
+type APIError struct {
+	Method     string
+	Endpoint   string
+	StatusCode int
+	Status     string
+	// Message is the "message" field of the Kimai error payload, if any.
+	Message string
+	// RetryAfter is the delay asked by the server in the Retry-After header.
+	RetryAfter time.Duration
+}
+
+func (e *APIError) Error() string {
+	if e.Message != "" {
+		return fmt.Sprintf("%s %s failed with %s: %s", e.Method, e.Endpoint, e.Status, e.Message)
+	}
+	return fmt.Sprintf("%s %s failed with %s", e.Method, e.Endpoint, e.Status)
+}
+
+// IsNoActiveTask reports whether err means that no timesheet is running.
+func IsNoActiveTask(err error) bool {
+	var e NoActiveTaskError
+	return errors.As(err, &e)
+}
+
+// IsNetworkError reports whether err is a connectivity problem (DNS, refused
+// connection, timeout...) rather than an answer from the server.
+func IsNetworkError(err error) bool {
+	var apiErr *APIError
+	if err == nil || errors.As(err, &apiErr) || errors.Is(err, context.Canceled) {
+		return false
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
+
+// IsForbidden reports whether the credentials are valid but lack the
+// permission for the request.
+func IsForbidden(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusForbidden
+}
+
+// IsAuthError reports whether the server rejected the credentials.
+func IsAuthError(err error) bool {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	return apiErr.StatusCode == http.StatusUnauthorized || apiErr.StatusCode == http.StatusForbidden
+}
@@ -0,0 +1,178 @@
+// Package kimai is a small client for the Kimai time-tracking REST API.
+package kimai
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+type Activity struct {
+	Id   int    `json:"id"`
+	Name string `json:"name"`
+	// ProjectId is only filled by FetchActivities, 0 for global activities.
+	ProjectId int `json:"-"`
+}
+
+type Project struct {
+	Id   int    `json:"id"`
+	Name string `json:"name"`
+	// CustomerId is 0 if unknown, see FetchCustomers for the names.
+	CustomerId int `json:"customer,omitempty"`
+}
+
+// UnmarshalJSON accepts the customer as an id, as in collections, or as an
+// object, as in expanded timesheets.
+func (p *Project) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Id       int             `json:"id"`
+		Name     string          `json:"name"`
+		Customer json.RawMessage `json:"customer"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	*p = Project{Id: raw.Id, Name: raw.Name}
+
+	if len(raw.Customer) == 0 || string(raw.Customer) == "null" {
+		return nil
+	}
+	if err := json.Unmarshal(raw.Customer, &p.CustomerId); err == nil {
+		return nil
+	}
+	var customer Customer
+	if err := json.Unmarshal(raw.Customer, &customer); err != nil {
+		return fmt.Errorf("project %d customer: %w", p.Id, err)
+	}
+	p.CustomerId = customer.Id
+	return nil
+}
+
+type Customer struct {
+	Id   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+// Task is a timesheet as returned by the (expanded) timesheet endpoints.
+type Task struct {
+	Id        int      `json:"id"`
+	Activity  Activity `json:"activity"`
+	Project   Project  `json:"project"`
+	StartTime string   `json:"begin"`
+	// EndTime is empty while the task is running.
+	EndTime string `json:"end,omitempty"`
+	// Duration in seconds, only meaningful once the task is stopped.
+	Duration    int      `json:"duration,omitempty"`
+	Description string   `json:"description,omitempty"`
+	Tags        []string `json:"tags,omitempty"`
+	// Billable and the rates are only returned by Kimai 2.
+	Billable   bool     `json:"billable,omitempty"`
+	Rate       float64  `json:"rate,omitempty"`
+	FixedRate  *float64 `json:"fixedRate,omitempty"`
+	HourlyRate *float64 `json:"hourlyRate,omitempty"`
+	// MetaFields are the custom fields of the timesheet, e.g. a cost center.
+	MetaFields []MetaField `json:"metaFields,omitempty"`
+}
+
+// MetaField is a custom field of a timesheet, its value being empty if unset.
+type MetaField struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// Meta returns the value of the named custom field, empty if unset.
+func (t Task) Meta(name string) string {
+	for _, field := range t.MetaFields {
+		if field.Name == name {
+			return field.Value
+		}
+	}
+	return ""
+}
+
+// BILLABLE_MARKER ends the label of billable tasks.
+const BILLABLE_MARKER = "💰"
+
+func (t Task) TextOutput() string {
+	p := fmt.Sprintf("[%s] %s", t.Project.Name, t.Activity.Name)
+	return p
+}
+
+// Label is TextOutput followed by the first description line, the tags and
+// BILLABLE_MARKER, e.g. "[ACME] Dev — fix login #bug 💰", truncated to
+// maxLength characters.
+func (t Task) Label(maxLength int) string {
+	label := t.TextOutput()
+	if description := strings.TrimSpace(strings.SplitN(t.Description, "\n", 2)[0]); description != "" {
+		label += " — " + description
+	}
+	for _, tag := range t.Tags {
+		label += " #" + tag
+	}
+	if t.Billable {
+		// keep the marker visible when truncating
+		return Truncate(label, maxLength-2) + " " + BILLABLE_MARKER
+	}
+	return Truncate(label, maxLength)
+}
+
+// Truncate shortens s to maxLength characters, ending with an ellipsis.
+func Truncate(s string, maxLength int) string {
+	runes := []rune(s)
+	if maxLength <= 0 || len(runes) <= maxLength {
+		return s
+	}
+	return strings.TrimSpace(string(runes[:maxLength-1])) + "…"
+}
+
+// Begin parses StartTime, the zero time is returned if it can't be parsed.
+func (t Task) Begin() time.Time {
+	begin, _ := ParseTime(t.StartTime)
+	return begin
+}
+
+// End parses EndTime, the zero time is returned while running or if it can't be parsed.
+func (t Task) End() time.Time {
+	end, _ := ParseTime(t.EndTime)
+	return end
+}
+
+// Elapsed is the time spent on the task so far.
+func (t Task) Elapsed() time.Duration {
+	return time.Since(t.Begin())
+}
+
+func (t Task) Running() bool {
+	return t.EndTime == ""
+}
+
+// Spent is the tracked time, the elapsed time for a running task.
+func (t Task) Spent() time.Duration {
+	if t.Running() {
+		return t.Elapsed()
+	}
+	if t.Duration == 0 {
+		// some endpoints leave the duration out
+		if begin, end := t.Begin(), t.End(); !begin.IsZero() && !end.IsZero() {
+			return end.Sub(begin)
+		}
+	}
+	return time.Duration(t.Duration) * time.Second
+}
+
+// Dedup keeps the first task of each project and activity pair.
+func Dedup(tasks []Task) []Task {
+	type pair struct{ project, activity int }
+	seen := map[pair]bool{}
+
+	var res []Task
+	for _, task := range tasks {
+		key := pair{task.Project.Id, task.Activity.Id}
+		if !seen[key] {
+			seen[key] = true
+			res = append(res, task)
+		}
+	}
+	return res
+}
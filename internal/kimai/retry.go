@@ -0,0 +1,79 @@
+package kimai
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// MAX_BACKOFF caps the exponential delay between two attempts.
+const MAX_BACKOFF = 30 * time.Second
+
+// retryable reports whether a failed request can be sent again. Restarting or
+// starting a task is not idempotent, so apart from GETs a request is only
+// retried when the server can't have processed it.
+func retryable(method string, err error) bool {
+	if errors.Is(err, context.Canceled) {
+		return false
+	}
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		switch apiErr.StatusCode {
+		case http.StatusTooManyRequests, http.StatusServiceUnavailable:
+			return true
+		}
+		return method == http.MethodGet && apiErr.StatusCode >= 500
+	}
+
+	var opErr *net.OpError
+	if errors.As(err, &opErr) && opErr.Op == "dial" {
+		return true
+	}
+	return method == http.MethodGet && IsNetworkError(err)
+}
+
+// delay is the wait before the given retry, honoring Retry-After.
+func (c *Client) delay(attempt int, err error) time.Duration {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.RetryAfter > 0 {
+		return apiErr.RetryAfter
+	}
+
+	backoff := c.backoff << attempt
+	if backoff <= 0 || backoff > MAX_BACKOFF {
+		backoff = MAX_BACKOFF
+	}
+	// full jitter on the upper half, so concurrent clients spread out
+	return backoff/2 + time.Duration(rand.Int63n(int64(backoff/2)+1))
+}
+
+// parseRetryAfter reads a Retry-After header, either in seconds or an HTTP date.
+func parseRetryAfter(header string, now time.Time) time.Duration {
+	if header == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(header); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(header); err == nil && date.After(now) {
+		return date.Sub(now)
+	}
+	return 0
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
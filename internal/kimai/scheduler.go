@@ -0,0 +1,81 @@
+package kimai
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// scheduler sends the requests of a client one at a time, at most rate per
+// second, so bursts of clicks and refreshes don't trip the rate limits or
+// mod_security rules in front of some servers.
+type scheduler struct {
+	// slot holds the request being sent
+	slot     chan struct{}
+	interval time.Duration
+	last     time.Time
+
+	mu       sync.Mutex
+	inflight map[string]*flight
+}
+
+// flight is a GET being performed, shared by the identical ones asked meanwhile.
+type flight struct {
+	done chan struct{}
+	data []byte
+	err  error
+}
+
+// newScheduler limits the requests to rate per second, only serializing them if 0.
+func newScheduler(rate float64) *scheduler {
+	s := &scheduler{slot: make(chan struct{}, 1)}
+	if rate > 0 {
+		s.interval = time.Duration(float64(time.Second) / rate)
+	}
+	return s
+}
+
+// acquire waits for the previous request to complete and the rate to allow
+// another one. release must be called once the response has been read.
+func (s *scheduler) acquire(ctx context.Context) (release func(), err error) {
+	select {
+	case s.slot <- struct{}{}:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	release = func() { <-s.slot }
+
+	if wait := time.Until(s.last.Add(s.interval)); wait > 0 {
+		if err := sleep(ctx, wait); err != nil {
+			release()
+			return nil, err
+		}
+	}
+	s.last = time.Now()
+	return release, nil
+}
+
+// merge runs fn for key unless the same key is already in flight, in which
+// case its result is shared. It is then bound to the context of the first call.
+func (s *scheduler) merge(key string, fn func() ([]byte, error)) ([]byte, error) {
+	s.mu.Lock()
+	if current, ok := s.inflight[key]; ok {
+		s.mu.Unlock()
+		<-current.done
+		return current.data, current.err
+	}
+	current := &flight{done: make(chan struct{})}
+	if s.inflight == nil {
+		s.inflight = make(map[string]*flight)
+	}
+	s.inflight[key] = current
+	s.mu.Unlock()
+
+	current.data, current.err = fn()
+
+	s.mu.Lock()
+	delete(s.inflight, key)
+	s.mu.Unlock()
+	close(current.done)
+	return current.data, current.err
+}
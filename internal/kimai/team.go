@@ -0,0 +1,97 @@
+package kimai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+const (
+	TEAM_ENDPOINT  = "timesheets?full=true&active=1&user=all&size=%d"
+	USERS_ENDPOINT = "users?visible=1"
+	TEAM_SIZE      = 100
+)
+
+type User struct {
+	Id       int    `json:"id"`
+	Username string `json:"username"`
+	Alias    string `json:"alias,omitempty"`
+}
+
+// Name is the alias if set, the username otherwise, or the id when the
+// token is not allowed to list the users.
+func (u User) Name() string {
+	switch {
+	case u.Alias != "":
+		return u.Alias
+	case u.Username != "":
+		return u.Username
+	}
+	return fmt.Sprintf("#%d", u.Id)
+}
+
+// TeamTask is a running timesheet of any user.
+type TeamTask struct {
+	Task
+	User User `json:"user"`
+}
+
+func (c *Client) FetchUsers(ctx context.Context) ([]User, error) {
+	var users []User
+	if err := c.do(ctx, http.MethodGet, USERS_ENDPOINT, nil, &users); err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+// FetchTeamActive returns the running timesheets of all the users, which
+// needs the view_other_timesheet permission (teamleads and admins), the
+// server answering 403 otherwise, see IsForbidden.
+func (c *Client) FetchTeamActive(ctx context.Context) ([]TeamTask, error) {
+	// the user is only an id in some versions, an object in others
+	var entries []struct {
+		Task
+		User json.RawMessage `json:"user"`
+	}
+	if err := c.do(ctx, http.MethodGet, fmt.Sprintf(TEAM_ENDPOINT, TEAM_SIZE), nil, &entries); err != nil {
+		return nil, err
+	}
+
+	tasks := make([]TeamTask, 0, len(entries))
+	unnamed := false
+	for _, entry := range entries {
+		task := TeamTask{Task: entry.Task}
+		var err error
+		if bytes.HasPrefix(bytes.TrimSpace(entry.User), []byte("{")) {
+			err = json.Unmarshal(entry.User, &task.User)
+		} else {
+			err = json.Unmarshal(entry.User, &task.User.Id)
+		}
+		if err != nil {
+			return nil, err
+		}
+		unnamed = unnamed || task.User.Username == ""
+		tasks = append(tasks, task)
+	}
+	if !unnamed {
+		return tasks, nil
+	}
+
+	// best effort, listing the users needs yet another permission
+	users, err := c.FetchUsers(ctx)
+	if err != nil {
+		return tasks, nil
+	}
+	byId := make(map[int]User, len(users))
+	for _, user := range users {
+		byId[user.Id] = user
+	}
+	for i := range tasks {
+		if user, ok := byId[tasks[i].User.Id]; ok {
+			tasks[i].User = user
+		}
+	}
+	return tasks, nil
+}
@@ -0,0 +1,65 @@
+package kimai
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// DATETIME_FORMAT is the HTML5 local datetime format Kimai expects for begin/end.
+const DATETIME_FORMAT = "2006-01-02T15:04:05"
+
+// TIME_LAYOUTS are the formats Kimai returns datetimes in: RFC3339 for Kimai 2
+// ("2023-05-19T10:20:00+02:00"), an offset without colon for Kimai 1
+// ("2019-11-05T10:00:00+0100"), and local time without offset.
+var TIME_LAYOUTS = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05-0700",
+	DATETIME_FORMAT,
+}
+
+// ParseTime parses a Kimai datetime. Times without offset are local.
+func ParseTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, fmt.Errorf("empty datetime")
+	}
+	for _, layout := range TIME_LAYOUTS {
+		if t, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("unsupported datetime %q", value)
+}
+
+const (
+	// DURATION_FORMAT_CLOCK renders hours and minutes, e.g. "7:05 h".
+	DURATION_FORMAT_CLOCK = "clock"
+	// DURATION_FORMAT_DECIMAL renders decimal hours, e.g. "7.08 h".
+	DURATION_FORMAT_DECIMAL = "decimal"
+	// DURATION_FORMAT_COMPACT renders e.g. "7h05m", or "5m" under an hour.
+	DURATION_FORMAT_COMPACT = "compact"
+)
+
+// DURATION_FORMATS lists the formats accepted by FormatDuration.
+var DURATION_FORMATS = []string{DURATION_FORMAT_CLOCK, DURATION_FORMAT_DECIMAL, DURATION_FORMAT_COMPACT}
+
+// FormatDuration renders d in one of DURATION_FORMATS, DURATION_FORMAT_CLOCK
+// if the format is unknown. Durations are truncated to the minute.
+func FormatDuration(d time.Duration, format string) string {
+	if d < 0 {
+		d = 0
+	}
+	minutes := int(d.Minutes())
+
+	switch strings.ToLower(format) {
+	case DURATION_FORMAT_DECIMAL:
+		return fmt.Sprintf("%.2f h", float64(minutes)/60)
+	case DURATION_FORMAT_COMPACT:
+		if minutes < 60 {
+			return fmt.Sprintf("%dm", minutes)
+		}
+		return fmt.Sprintf("%dh%02dm", minutes/60, minutes%60)
+	default:
+		return fmt.Sprintf("%d:%02d h", minutes/60, minutes%60)
+	}
+}
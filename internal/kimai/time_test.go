@@ -0,0 +1,62 @@
+package kimai
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseTime(t *testing.T) {
+	tests := []struct {
+		value string
+		want  time.Time
+		err   bool
+	}{
+		{value: "2023-05-19T10:20:00+01:00", want: time.Date(2023, 5, 19, 9, 20, 0, 0, time.UTC)},
+		{value: "2019-11-05T10:00:00+0100", want: time.Date(2019, 11, 5, 9, 0, 0, 0, time.UTC)},
+		{value: "2023-05-19T10:20:00", want: time.Date(2023, 5, 19, 10, 20, 0, 0, time.Local)},
+		{value: "", err: true},
+		{value: "19.05.2023 10:20", err: true},
+		{value: "2023-05-19", err: true},
+	}
+
+	for _, test := range tests {
+		got, err := ParseTime(test.value)
+		if test.err {
+			if err == nil {
+				t.Errorf("ParseTime(%q) = %v, want an error", test.value, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseTime(%q) failed: %v", test.value, err)
+			continue
+		}
+		if !got.Equal(test.want) {
+			t.Errorf("ParseTime(%q) = %v, want %v", test.value, got, test.want)
+		}
+	}
+}
+
+func TestFormatDuration(t *testing.T) {
+	tests := []struct {
+		duration time.Duration
+		format   string
+		want     string
+	}{
+		{7*time.Hour + 5*time.Minute, DURATION_FORMAT_CLOCK, "7:05 h"},
+		{7*time.Hour + 5*time.Minute, DURATION_FORMAT_DECIMAL, "7.08 h"},
+		{7*time.Hour + 5*time.Minute, DURATION_FORMAT_COMPACT, "7h05m"},
+		{5*time.Minute + 59*time.Second, DURATION_FORMAT_CLOCK, "0:05 h"},
+		{5 * time.Minute, DURATION_FORMAT_DECIMAL, "0.08 h"},
+		{5 * time.Minute, DURATION_FORMAT_COMPACT, "5m"},
+		{-time.Hour, DURATION_FORMAT_COMPACT, "0m"},
+		{90 * time.Minute, "DECIMAL", "1.50 h"},
+		{90 * time.Minute, "unknown", "1:30 h"},
+	}
+
+	for _, test := range tests {
+		if got := FormatDuration(test.duration, test.format); got != test.want {
+			t.Errorf("FormatDuration(%v, %q) = %q, want %q", test.duration, test.format, got, test.want)
+		}
+	}
+}
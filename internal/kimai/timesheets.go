@@ -0,0 +1,40 @@
+package kimai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+const (
+	TIMESHEETS_ENDPOINT = "timesheets?full=true&size=%d&page=%d&begin=%s&end=%s"
+	TIMESHEETS_PAGE     = 200
+)
+
+// FetchTimesheets returns the timesheets of the current user starting
+// between begin and end.
+func (c *Client) FetchTimesheets(ctx context.Context, begin time.Time, end time.Time) ([]Task, error) {
+	var all []Task
+	for page := 1; ; page++ {
+		endpoint := fmt.Sprintf(TIMESHEETS_ENDPOINT, TIMESHEETS_PAGE, page,
+			url.QueryEscape(begin.Format(DATETIME_FORMAT)), url.QueryEscape(end.Format(DATETIME_FORMAT)))
+
+		var tasks []Task
+		if err := c.do(ctx, http.MethodGet, endpoint, nil, &tasks); err != nil {
+			// Kimai answers 404 when asking for a page past the last one
+			var apiErr *APIError
+			if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound && page > 1 {
+				return all, nil
+			}
+			return nil, err
+		}
+
+		all = append(all, tasks...)
+		if len(tasks) < TIMESHEETS_PAGE {
+			return all, nil
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package kimai
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+const VERSION_ENDPOINT = "version"
+
+type Version struct {
+	Version   string `json:"version"`
+	VersionId int    `json:"versionId"`
+	Copyright string `json:"copyright"`
+}
+
+// Major is the major Kimai version, 0 if unknown.
+func (v Version) Major() int {
+	if v.VersionId > 0 {
+		return v.VersionId / 10000
+	}
+	major, _ := strconv.Atoi(strings.SplitN(v.Version, ".", 2)[0])
+	return major
+}
+
+// Id is VersionId, or computed from Version on servers not sending it.
+func (v Version) Id() int {
+	if v.VersionId > 0 {
+		return v.VersionId
+	}
+	id := 0
+	parts := strings.SplitN(strings.SplitN(v.Version, "-", 2)[0], ".", 3)
+	for i, factor := range []int{10000, 100, 1} {
+		if i < len(parts) {
+			n, _ := strconv.Atoi(parts[i])
+			id += n * factor
+		}
+	}
+	return id
+}
+
+func (c *Client) FetchVersion(ctx context.Context) (Version, error) {
+	var version Version
+	err := c.do(ctx, http.MethodGet, VERSION_ENDPOINT, nil, &version)
+	return version, err
+}
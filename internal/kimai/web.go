@@ -0,0 +1,23 @@
+package kimai
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	TIMESHEETS_WEB_PAGE     = "timesheet/"
+	TIMESHEET_EDIT_WEB_PAGE = "timesheet/%d/edit"
+)
+
+// WebURL is the address of a page of the Kimai web interface, whose routes
+// are prefixed with the locale, e.g. https://host/en/timesheet/.
+func (c *Client) WebURL(locale string, page string) string {
+	root := strings.TrimSuffix(c.baseURL, "api/")
+	return root + locale + "/" + page
+}
+
+// TimesheetURL is the web page to edit the given task.
+func (c *Client) TimesheetURL(locale string, taskId int) string {
+	return c.WebURL(locale, fmt.Sprintf(TIMESHEET_EDIT_WEB_PAGE, taskId))
+}
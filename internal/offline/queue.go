@@ -0,0 +1,135 @@
+// Package offline journals the actions that could not reach the Kimai server
+// so they can be replayed once it is reachable again.
+package offline
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"qckm/internal/kimai"
+)
+
+const (
+	STOP    = "stop"
+	RESTART = "restart"
+)
+
+type Action struct {
+	Kind     string    `json:"kind"`
+	TaskId   int       `json:"task_id"`
+	QueuedAt time.Time `json:"queued_at"`
+}
+
+// Queue is a list of pending actions persisted as a JSON file.
+type Queue struct {
+	path    string
+	mu      sync.Mutex
+	actions []Action
+	// replaying is held by Replay, which runs the requests without mu so
+	// Len and Add don't wait for a slow server
+	replaying sync.Mutex
+}
+
+// Open loads the journal at path, a missing file is an empty queue.
+func Open(path string) (*Queue, error) {
+	q := &Queue{path: path}
+
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return q, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &q.actions); err != nil {
+		return nil, fmt.Errorf("corrupted offline journal %s: %w", path, err)
+	}
+	return q, nil
+}
+
+func (q *Queue) Add(kind string, taskId int) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.actions = append(q.actions, Action{Kind: kind, TaskId: taskId, QueuedAt: time.Now()})
+	return q.save()
+}
+
+func (q *Queue) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	return len(q.actions)
+}
+
+// Replay runs the queued actions in order. It stops at the first network
+// error, keeping that action and the following ones for a later attempt.
+// Actions rejected by the server are dropped and their errors returned.
+func (q *Queue) Replay(ctx context.Context, client *kimai.Client) ([]error, error) {
+	q.replaying.Lock()
+	defer q.replaying.Unlock()
+
+	q.mu.Lock()
+	actions := append([]Action(nil), q.actions...)
+	q.mu.Unlock()
+
+	var rejected []error
+	done := 0
+	for _, action := range actions {
+
+		var err error
+		switch action.Kind {
+		case STOP:
+			// the task ends when the user stopped it, not when the server is
+			// back, and isn't rounded as the queue doesn't know why it stopped
+			if action.QueuedAt.IsZero() {
+				err = client.StopTask(ctx, action.TaskId)
+			} else {
+				err = client.StopTaskAt(ctx, action.TaskId, action.QueuedAt)
+			}
+		case RESTART:
+			_, err = client.RestartTask(ctx, action.TaskId)
+		default:
+			err = fmt.Errorf("unknown action %q", action.Kind)
+		}
+
+		if kimai.IsNetworkError(err) {
+			break
+		}
+		if err != nil {
+			rejected = append(rejected, fmt.Errorf("queued %s of task %d: %w", action.Kind, action.TaskId, err))
+		}
+		done++
+	}
+
+	// the actions added meanwhile come after the replayed ones
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.actions = q.actions[done:]
+	return rejected, q.save()
+}
+
+func (q *Queue) save() error {
+	if len(q.actions) == 0 {
+		err := os.Remove(q.path)
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+
+	data, err := json.MarshalIndent(q.actions, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(q.path), 0700); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(q.path, data, 0600)
+}
@@ -0,0 +1,79 @@
+// Package rounding moves the begin and end of a timesheet to billing
+// increments when it is stopped.
+package rounding
+
+import "time"
+
+const (
+	// UP lengthens the entries: the begin is rounded down and the end up.
+	UP = "up"
+	// DOWN shortens the entries: the begin is rounded up and the end down.
+	DOWN = "down"
+	// NEAREST rounds both to the closest increment.
+	NEAREST = "nearest"
+)
+
+var MODES = []string{UP, DOWN, NEAREST}
+
+type Rules struct {
+	// Begin and End are the increments, 0 keeping the time as is.
+	Begin time.Duration
+	End   time.Duration
+	// Mode is UP, DOWN or NEAREST.
+	Mode string
+	// Minimum is the shortest entry, the end being pushed back if needed.
+	Minimum time.Duration
+}
+
+// Enabled reports whether Apply changes anything.
+func (r Rules) Enabled() bool {
+	return r.Begin > 0 || r.End > 0 || r.Minimum > 0
+}
+
+// Apply returns the rounded begin and end of an entry.
+func (r Rules) Apply(begin time.Time, end time.Time) (time.Time, time.Time) {
+	switch r.Mode {
+	case DOWN:
+		begin, end = ceil(begin, r.Begin), floor(end, r.End)
+	case NEAREST:
+		begin, end = nearest(begin, r.Begin), nearest(end, r.End)
+	default:
+		begin, end = floor(begin, r.Begin), ceil(end, r.End)
+	}
+
+	// rounding a short entry down can leave it empty
+	if end.Before(begin) {
+		end = begin
+	}
+	if end.Sub(begin) < r.Minimum {
+		end = begin.Add(r.Minimum)
+	}
+	return begin, end
+}
+
+// floor rounds t down to a multiple of step since its local midnight, so
+// hours are aligned whatever the time zone offset.
+func floor(t time.Time, step time.Duration) time.Time {
+	if step <= 0 {
+		return t
+	}
+	year, month, day := t.Date()
+	midnight := time.Date(year, month, day, 0, 0, 0, 0, t.Location())
+	return midnight.Add(t.Sub(midnight).Truncate(step))
+}
+
+func ceil(t time.Time, step time.Duration) time.Time {
+	rounded := floor(t, step)
+	if rounded.Equal(t) {
+		return t
+	}
+	return rounded.Add(step)
+}
+
+func nearest(t time.Time, step time.Duration) time.Time {
+	down := floor(t, step)
+	if t.Sub(down) < step/2 {
+		return down
+	}
+	return ceil(t, step)
+}
@@ -0,0 +1,121 @@
+// Package stats aggregates timesheets into per day and per project totals.
+package stats
+
+import (
+	"sort"
+	"time"
+
+	"qckm/internal/kimai"
+)
+
+type ProjectTotal struct {
+	Project string
+	Total   time.Duration
+}
+
+type DayTotal struct {
+	Day   time.Time
+	Total time.Duration
+}
+
+type Summary struct {
+	Total      time.Duration
+	PerProject []ProjectTotal
+	PerDay     []DayTotal
+}
+
+// StartOfDay returns midnight of t's day, in t's location.
+func StartOfDay(t time.Time) time.Time {
+	year, month, day := t.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, t.Location())
+}
+
+// StartOfWeek returns the Monday midnight of t's week.
+func StartOfWeek(t time.Time) time.Time {
+	offset := (int(t.Weekday()) + 6) % 7
+	return StartOfDay(t).AddDate(0, 0, -offset)
+}
+
+// Summarize totals the tasks that started in [from, to). Projects are sorted
+// by decreasing total, days chronologically.
+func Summarize(tasks []kimai.Task, from time.Time, to time.Time) Summary {
+	var summary Summary
+	projects := map[string]time.Duration{}
+	days := map[time.Time]time.Duration{}
+
+	for _, task := range tasks {
+		begin := task.Begin().In(from.Location())
+		if begin.Before(from) || !begin.Before(to) {
+			continue
+		}
+
+		spent := task.Spent()
+		summary.Total += spent
+		projects[task.Project.Name] += spent
+		days[StartOfDay(begin)] += spent
+	}
+
+	for project, total := range projects {
+		summary.PerProject = append(summary.PerProject, ProjectTotal{Project: project, Total: total})
+	}
+	sort.Slice(summary.PerProject, func(i, j int) bool {
+		if summary.PerProject[i].Total == summary.PerProject[j].Total {
+			return summary.PerProject[i].Project < summary.PerProject[j].Project
+		}
+		return summary.PerProject[i].Total > summary.PerProject[j].Total
+	})
+
+	for day, total := range days {
+		summary.PerDay = append(summary.PerDay, DayTotal{Day: day, Total: total})
+	}
+	sort.Slice(summary.PerDay, func(i, j int) bool {
+		return summary.PerDay[i].Day.Before(summary.PerDay[j].Day)
+	})
+
+	return summary
+}
+
+// WORK_DAYS is the number of days, from Monday, a weekly target is spread over.
+const WORK_DAYS = 5
+
+// Expected is the share of the weekly target due by the start of t's day,
+// counting WORK_DAYS per week.
+func Expected(target time.Duration, t time.Time) time.Duration {
+	days := (int(t.Weekday()) + 6) % 7
+	if days > WORK_DAYS {
+		days = WORK_DAYS
+	}
+	return target * time.Duration(days) / WORK_DAYS
+}
+
+// Untracked is the time between the tasks started in [from, to) that none
+// of them covers, from the first begin to the last end. Running tasks count
+// until to.
+func Untracked(tasks []kimai.Task, from time.Time, to time.Time) time.Duration {
+	type span struct{ begin, end time.Time }
+	var spans []span
+	for _, task := range tasks {
+		begin := task.Begin().In(from.Location())
+		if begin.Before(from) || !begin.Before(to) {
+			continue
+		}
+		end := to
+		if !task.Running() && task.End().Before(to) {
+			end = task.End()
+		}
+		spans = append(spans, span{begin, end})
+	}
+	sort.Slice(spans, func(i, j int) bool { return spans[i].begin.Before(spans[j].begin) })
+
+	var untracked time.Duration
+	for i := 1; i < len(spans); i++ {
+		covered := spans[i-1].end
+		if spans[i].begin.After(covered) {
+			untracked += spans[i].begin.Sub(covered)
+		} else if spans[i].end.Before(covered) {
+			// nested in the previous one
+			spans[i].end = covered
+		}
+	}
+	return untracked
+}
@@ -0,0 +1,83 @@
+// Package suggest maps the git branch of a repository, or the title of the
+// focused window, to a project and activity to start.
+package suggest
+
+import (
+	"errors"
+	"os/exec"
+	"regexp"
+	"strings"
+)
+
+const (
+	BRANCH = "branch"
+	WINDOW = "window"
+)
+
+var SOURCES = []string{BRANCH, WINDOW}
+
+var ErrUnsupported = errors.New("the focused window title is not available on this desktop")
+
+// Rule suggests a project and activity when Pattern matches the text of
+// Source, or of both sources if empty.
+type Rule struct {
+	Pattern    *regexp.Regexp
+	Source     string
+	ProjectId  int
+	ActivityId int
+	// Description is expanded with the submatches of Pattern, e.g. "$1".
+	Description string
+}
+
+// Suggestion is the rule matching a branch or window title.
+type Suggestion struct {
+	ProjectId   int
+	ActivityId  int
+	Description string
+	Source      string
+	// Text is the branch or window title matched.
+	Text string
+}
+
+// Match returns the suggestion of the first rule matching text, read from source.
+func Match(rules []Rule, source string, text string) (Suggestion, bool) {
+	for _, rule := range rules {
+		if rule.Source != "" && rule.Source != source {
+			continue
+		}
+		submatches := rule.Pattern.FindStringSubmatchIndex(text)
+		if submatches == nil {
+			continue
+		}
+		description := string(rule.Pattern.ExpandString(nil, rule.Description, text, submatches))
+		return Suggestion{
+			ProjectId:   rule.ProjectId,
+			ActivityId:  rule.ActivityId,
+			Description: description,
+			Source:      source,
+			Text:        text,
+		}, true
+	}
+	return Suggestion{}, false
+}
+
+// Branch is the branch checked out in the repository at dir, empty when
+// the HEAD is detached.
+func Branch(dir string) (string, error) {
+	out, err := exec.Command("git", "-C", dir, "rev-parse", "--abbrev-ref", "HEAD").Output()
+	if err != nil {
+		return "", err
+	}
+	branch := strings.TrimSpace(string(out))
+	if branch == "HEAD" {
+		return "", nil
+	}
+	return branch, nil
+}
+
+// WindowTitle is the title of the focused window, with xdotool on X11, from
+// System Events on macOS (which needs the accessibility permission) and
+// user32 on Windows. Wayland doesn't tell it.
+func WindowTitle() (string, error) {
+	return windowTitle()
+}
@@ -0,0 +1,33 @@
+//go:build !windows
+
+package suggest
+
+import (
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+const FRONT_WINDOW_SCRIPT = `tell application "System Events" to tell (first application process whose frontmost is true) to get name of front window`
+
+func windowTitle() (string, error) {
+	var cmd *exec.Cmd
+	switch {
+	case runtime.GOOS == "darwin":
+		cmd = exec.Command("osascript", "-e", FRONT_WINDOW_SCRIPT)
+	case os.Getenv("DISPLAY") != "":
+		cmd = exec.Command("xdotool", "getactivewindow", "getwindowname")
+	default:
+		return "", ErrUnsupported
+	}
+
+	out, err := cmd.Output()
+	if err != nil {
+		if _, ok := err.(*exec.Error); ok {
+			return "", ErrUnsupported
+		}
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
@@ -0,0 +1,26 @@
+package suggest
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+var (
+	user32                  = syscall.NewLazyDLL("user32.dll")
+	procGetForegroundWindow = user32.NewProc("GetForegroundWindow")
+	procGetWindowTextW      = user32.NewProc("GetWindowTextW")
+)
+
+// MAX_TITLE_LENGTH in UTF-16 code units, longer titles are truncated.
+const MAX_TITLE_LENGTH = 512
+
+func windowTitle() (string, error) {
+	window, _, _ := procGetForegroundWindow.Call()
+	if window == 0 {
+		return "", nil
+	}
+
+	title := make([]uint16, MAX_TITLE_LENGTH)
+	n, _, _ := procGetWindowTextW.Call(window, uintptr(unsafe.Pointer(&title[0])), uintptr(len(title)))
+	return syscall.UTF16ToString(title[:n]), nil
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/zalando/go-keyring"
+	"golang.org/x/term"
+
+	"qckm/internal/kimai"
+)
+
+// loginCommand reads the API token from the terminal and stores it in the OS keyring.
+func loginCommand(ctx context.Context, client *kimai.Client, args []string, asJson bool) error {
+	config := currentConfig()
+	if config.URL == "" || config.Username == "" {
+		return fmt.Errorf("url and user must be set in the config file before logging in")
+	}
+
+	fmt.Printf("API token for %s: ", config.KeyringAccount())
+	token, err := term.ReadPassword(int(os.Stdin.Fd()))
+	fmt.Println()
+	if err != nil {
+		return err
+	}
+	if len(token) == 0 {
+		return fmt.Errorf("empty token")
+	}
+
+	// make sure the token works before storing it
+	config.Token = string(token)
+	if _, err := NewClient(config).FetchActive(ctx); err != nil && !kimai.IsNoActiveTask(err) {
+		return err
+	}
+
+	if err := keyring.Set(KEYRING_SERVICE, config.KeyringAccount(), config.Token); err != nil {
+		return fmt.Errorf("storing the token in the keyring failed: %w", err)
+	}
+	if asJson {
+		return printJson(map[string]interface{}{"result": "stored", "account": config.KeyringAccount()})
+	}
+	fmt.Printf("token stored for %s, it can be removed from the config file\n", config.KeyringAccount())
+	return nil
+}
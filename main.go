@@ -1,307 +1,121 @@
 package main
 
 import (
-	_ "embed"
-	"encoding/json"
+	"errors"
+	"flag"
 	"fmt"
-	"io/ioutil"
-	"net/http"
+	"log/slog"
 	"os"
-	"strings"
-	"time"
+	"path/filepath"
+	"sync/atomic"
 
 	"github.com/heb-dtc/systray"
-	"gopkg.in/yaml.v2"
-)
-
-//go:embed "assets/icon.ico"
-var icon []byte
-
-const (
-	RECENTS_ENDOINT  = "recent?size=10"
-	ACTIVE_ENDOINT   = "active"
-	RESTART_ENDPOINT = "%d/restart"
-	STOP_ENDPOINT    = "%d/stop"
-)
-
-type Config struct {
-	URL      string `yaml:"url"`
-	Username string `yaml:"user"`
-	Token    string `yaml:"token"`
-}
 
-var (
-	config      Config
-	recentTasks []Task
-	activeTask  Task
+	"qckm/internal/demo"
+	"qckm/internal/i18n"
+	"qckm/internal/logging"
 )
 
-type NoActiveTaskErrror struct{}
-
-func (e NoActiveTaskErrror) Error() string {
-	return fmt.Sprintf("No active task")
-}
-
-type Activity struct {
-	Name string `json:"name"`
-}
-
-type Project struct {
-	Name string `json:"name"`
-}
+// activeConfig is the config in use. ReloadConfig replaces it while the
+// menu, refresh and watcher goroutines read it, see currentConfig.
+var activeConfig atomic.Pointer[Config]
 
-type Task struct {
-	Id        int      `json:"id"`
-	Activity  Activity `json:"activity"`
-	Project   Project  `json:"project"`
-	StartTime string   `json:"begin"`
-}
-
-func (t Task) TextOutput() string {
-	p := fmt.Sprintf("[%s] %s", t.Project.Name, t.Activity.Name)
-	return p
-}
-
-func (t Task) TaskDuration() string {
-	// format StartTime to match RFC3339 format -> YYYY:MM:DDTHH:MM:SS+00:00
-	var builder strings.Builder
-	for index, char := range t.StartTime {
-		builder.WriteString(string(char))
-		if index == len(activeTask.StartTime)-3 {
-			builder.WriteString(":")
-		}
+// currentConfig returns the config in use, a zero Config until main stores
+// it, e.g. while RunSetup notifies. Callers reading several settings take it
+// once, for a consistent view across a reload.
+func currentConfig() Config {
+	if config := activeConfig.Load(); config != nil {
+		return *config
 	}
-	timeString := builder.String()
-
-	taskTime, _ := time.Parse(time.RFC3339, timeString)
-	duration := time.Since(taskTime)
-
-    hours := int(duration.Seconds() / 3600)
-    minutes := int(duration.Seconds() / 60) % 60
-	return fmt.Sprintf("%d:%d h", hours, minutes)
+	return Config{}
 }
 
-func main() {
-	homeDir, err := os.UserHomeDir()
-	file, err := ioutil.ReadFile(homeDir + "/.config/qckm/qckm.yaml")
-	if err != nil {
-		panic("config file failed to open -> " + err.Error())
-	}
-
-	config = Config{}
-	err = yaml.Unmarshal(file, &config)
-	if err != nil {
-		panic("config file parsing err -> " + err.Error())
-	}
-
-	systray.Run(onReady, onExit)
+func setConfig(c Config) {
+	activeConfig.Store(&c)
 }
 
-func onReady() {
-	kimaiClient := http.Client{}
-	systray.SetIcon(icon)
-	recentMenu := systray.AddMenuItem("Recent", "")
-	systray.AddSeparator()
-	activeMenu := systray.AddMenuItem("Active", "")
-	systray.AddSeparator()
-	refreshAction := systray.AddMenuItem("Refresh", "Refresh the menu")
-	systray.AddSeparator()
-	systray.AddMenuItem("Quit", "Quit the whole app")
-
-	go func() {
-		for {
-			select {
-			case <-refreshAction.ClickedCh:
-				SetupMenu(kimaiClient, recentMenu, activeMenu)
-			}
+func main() {
+	profile := flag.String("profile", "", "name of the config profile to use")
+	verbose := flag.Bool("verbose", false, "log at debug level, to stderr too")
+	configFlag := flag.String("config", "", "path of the config file")
+	demoFlag := flag.Bool("demo", false, "run against an in-process fake Kimai with canned data")
+	flag.Usage = func() {
+		fmt.Fprint(os.Stderr, USAGE)
+	}
+	flag.Parse()
+
+	var config Config
+	var err error
+	configPath, err = FindConfig(*configFlag)
+	if errors.As(err, &NoConfigError{}) {
+		if *demoFlag {
+			// the demo runs on the defaults without a config file
+			configPath, err = "", nil
+		} else {
+			err = RunSetup(configPath)
 		}
-	}()
-
-	SetupMenu(kimaiClient, recentMenu, activeMenu)
-}
-
-func onExit() {
-}
-
-func SetupMenu(client http.Client, recentMenu *systray.MenuItem, activeMenu *systray.MenuItem) {
-	GetMenuState(client)
-	recentMenu.RemoveSubMenuItems()
-	activeMenu.RemoveSubMenuItems()
-
-	for i, task := range recentTasks {
-		recentEntry := recentMenu.AddSubMenuItem(task.TextOutput(), "")
-		go func(idx int) {
-			for {
-				select {
-				case <-recentEntry.ClickedCh:
-					task := recentTasks[idx]
-					fmt.Printf("%s clicked \n", task.Project.Name)
-					err := RestartTask(task.Id, client)
-					if err == nil {
-						SetupMenu(client, recentMenu, activeMenu)
-					}
-				}
-			}
-		}(i)
-
-		i++
-	}
-
-	if activeTask.Id <= 0 {
-		activeMenu.Disable()
-	} else {
-		activeMenu.Enable()
-		activeTaskItem := fmt.Sprintf("%s (%s)", activeTask.TextOutput(), activeTask.TaskDuration())
-		activeMenu.AddSubMenuItem(activeTaskItem, "")
-		stopItem := activeMenu.AddSubMenuItem("Stop", "")
-		go func() {
-			for {
-				select {
-				case <-stopItem.ClickedCh:
-					err := StopTask(activeTask.Id, client)
-					if err == nil {
-						SetupMenu(client, recentMenu, activeMenu)
-					}
-				}
-			}
-		}()
-	}
-}
-
-func GetMenuState(client http.Client) {
-	recent, err := FetchRecent(client)
-	if err == nil {
-		recentTasks = recent
-	}
-
-	active, err := FetchActive(client)
-	if err == nil {
-		activeTask = active
-	} else {
-		activeTask = *new(Task)
-	}
-}
-
-func BuildRequest(method string, endpoint string) (*http.Request, error) {
-	requestUrl := config.URL + endpoint
-	fmt.Println("---- prepare request", method, " ", requestUrl)
-	req, err := http.NewRequest(method, requestUrl, nil)
-	if err != nil {
-		fmt.Println(err)
-		return nil, err
 	}
-
-	req.Header.Set("X-AUTH-USER", config.Username)
-	req.Header.Set("X-AUTH-TOKEN", config.Token)
-	return req, nil
-}
-
-func StopTask(id int, client http.Client) error {
-	fmt.Println("Stopping task with id ", id)
-
-	req, err := BuildRequest(http.MethodPatch, fmt.Sprintf(STOP_ENDPOINT, id))
 	if err != nil {
-		return err
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
 	}
-
-	_, err = client.Do(req)
-	if err != nil {
-		fmt.Println(err)
-		return err
+	if configPath == "" {
+		config, err = ParseConfig(nil, *profile)
+	} else {
+		config, err = LoadConfig(configPath, *profile)
 	}
-
-	return nil
-}
-
-func RestartTask(id int, client http.Client) error {
-	req, err := BuildRequest(http.MethodPatch, fmt.Sprintf(RESTART_ENDPOINT, id))
 	if err != nil {
-		return err
+		fmt.Fprintln(os.Stderr, "config file failed to load ->", err)
+		os.Exit(1)
+	}
+	if *demoFlag || config.Demo {
+		server, err := demo.Start()
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "demo server failed to start ->", err)
+			os.Exit(1)
+		}
+		defer server.Close()
+		config = config.WithDemo(server.URL())
 	}
+	setConfig(config)
 
-	_, err = client.Do(req)
-	if err != nil {
-		fmt.Println(err)
-		return err
+	if config.InsecureSkipVerify {
+		fmt.Fprintln(os.Stderr, "warning: insecure_skip_verify is set, the server certificate is not checked")
 	}
 
-	return nil
-}
-
-func FetchActive(client http.Client) (Task, error) {
-	var active Task
-
-	req, err := BuildRequest(http.MethodGet, ACTIVE_ENDOINT)
-	if err != nil {
-		return active, err
+	level, _ := logging.ParseLevel(config.LogLevel)
+	if *verbose {
+		level = slog.LevelDebug
 	}
-
-	res, err := client.Do(req)
+	logFile, err := logging.Setup(filepath.Join(StateDir(), "qckm.log"), level, *verbose)
 	if err != nil {
-		fmt.Println(err)
-		return active, err
-	}
-
-	if res.Body != nil {
-		defer res.Body.Close()
-	}
-
-	if res.StatusCode != 200 {
-		fmt.Println("request failed with code -> " + res.Status)
-	}
-
-	body, readErr := ioutil.ReadAll(res.Body)
-	if readErr != nil {
-		fmt.Println(readErr)
-		return active, readErr
-	}
-
-	var activeRes []Task
-	jsonErr := json.Unmarshal(body, &activeRes)
-	if jsonErr != nil {
-		fmt.Println(jsonErr)
-		return active, jsonErr
-	}
-
-	if len(activeRes) > 0 {
-		return activeRes[0], nil
+		fmt.Fprintln(os.Stderr, "logging to file disabled ->", err)
 	} else {
-		return active, NoActiveTaskErrror{}
-	}
-}
-
-func FetchRecent(client http.Client) ([]Task, error) {
-	req, err := BuildRequest(http.MethodGet, RECENTS_ENDOINT)
-	if err != nil {
-		return nil, err
+		defer logFile.Close()
 	}
 
-	res, err := client.Do(req)
-	if err != nil {
-		fmt.Println(err)
-		return nil, err
-	}
-
-	if res.Body != nil {
-		defer res.Body.Close()
-	}
-
-	if res.StatusCode != 200 {
-		fmt.Println("request failed with code -> " + res.Status)
-	}
-
-	body, readErr := ioutil.ReadAll(res.Body)
-	if readErr != nil {
-		fmt.Println(readErr)
-		return nil, readErr
-	}
-
-	var recent []Task
-	jsonErr := json.Unmarshal(body, &recent)
-	if jsonErr != nil {
-		fmt.Println(jsonErr)
-		return nil, jsonErr
+	args := flag.Args()
+	if len(args) > 0 && args[0] != "tray" {
+		code := RunCommand(NewClient(config), args)
+		if logFile != nil {
+			logFile.Close()
+		}
+		os.Exit(code)
+	}
+
+	// already validated by ParseConfig, the CLI output stays in English
+	i18n.SetLanguage(config.Language)
+	app := NewApp(config)
+	if err := app.ServeControl(); err != nil {
+		profile, callErr := activateRunning()
+		if callErr != nil {
+			fmt.Fprintln(os.Stderr, err)
+		} else {
+			fmt.Fprintf(os.Stderr, "qckm is already running with profile %q, use qckm ctl to control it\n", profile)
+		}
+		os.Exit(1)
 	}
 
-	return recent, nil
+	slog.Info("starting tray", "profile", config.SelectedProfile, "language", i18n.Language())
+	systray.Run(func() { onReady(app) }, onExit)
 }
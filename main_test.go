@@ -0,0 +1,14 @@
+package main
+
+import "testing"
+
+func TestNotifyBeforeSetConfig(t *testing.T) {
+	activeConfig.Store(nil)
+	// no notifier to run, Notify only logs the failure
+	t.Setenv("PATH", t.TempDir())
+
+	Notify("qckm is set up", "Config written to qckm.yaml")
+	if !currentConfig().NotificationsEnabled() {
+		t.Errorf("notifications are disabled before the config is set")
+	}
+}
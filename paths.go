@@ -0,0 +1,100 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+const (
+	CONFIG_FILE = "qckm.yaml"
+	// CONFIG_ENV overrides the config file location, the --config flag taking precedence.
+	CONFIG_ENV = "QCKM_CONFIG"
+)
+
+// ConfigDir holds the config file and the tray state: $XDG_CONFIG_HOME/qckm
+// (~/.config/qckm) on Linux, ~/Library/Application Support/qckm on macOS and
+// %AppData%\qckm on Windows. ~/.config/qckm is kept if it already exists.
+func ConfigDir() string {
+	homeDir, _ := os.UserHomeDir()
+	legacy := filepath.Join(homeDir, ".config", "qckm")
+
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return legacy
+	}
+	dir = filepath.Join(dir, "qckm")
+	if _, err := os.Stat(filepath.Join(dir, CONFIG_FILE)); err != nil {
+		if _, err := os.Stat(filepath.Join(legacy, CONFIG_FILE)); err == nil {
+			return legacy
+		}
+	}
+	return dir
+}
+
+// StateDir holds the log files: $XDG_STATE_HOME/qckm (~/.local/state/qckm) on
+// Linux, ~/Library/Logs/qckm on macOS and %LocalAppData%\qckm on Windows.
+func StateDir() string {
+	homeDir, _ := os.UserHomeDir()
+
+	switch runtime.GOOS {
+	case "darwin":
+		return filepath.Join(homeDir, "Library", "Logs", "qckm")
+	case "windows":
+		if dir, err := os.UserCacheDir(); err == nil {
+			return filepath.Join(dir, "qckm")
+		}
+	}
+
+	if dir := os.Getenv("XDG_STATE_HOME"); dir != "" {
+		return filepath.Join(dir, "qckm")
+	}
+	return filepath.Join(homeDir, ".local", "state", "qckm")
+}
+
+// NoConfigError is returned by FindConfig when no config file exists.
+type NoConfigError struct {
+	Searched []string
+}
+
+func (e NoConfigError) Error() string {
+	return fmt.Sprintf("no config file found (looked for %s), create one or pass --config",
+		strings.Join(e.Searched, ", "))
+}
+
+// FindConfig resolves the config file: the explicit path if not empty, then
+// $QCKM_CONFIG, then qckm.yaml in ConfigDir.
+func FindConfig(explicit string) (string, error) {
+	source, path := "--config", explicit
+	if path == "" {
+		source, path = "$"+CONFIG_ENV, os.Getenv(CONFIG_ENV)
+	}
+
+	if path != "" {
+		if _, err := os.Stat(path); err != nil {
+			return "", fmt.Errorf("config file %s given by %s: %w", path, source, err)
+		}
+		return path, nil
+	}
+
+	path = filepath.Join(ConfigDir(), CONFIG_FILE)
+	if _, err := os.Stat(path); err != nil {
+		return path, NoConfigError{Searched: []string{path}}
+	}
+	return path, nil
+}
+
+// SocketPath is the control socket of the tray, in $XDG_RUNTIME_DIR if set,
+// StateDir otherwise. The demo has its own, to run next to the real tray.
+func SocketPath() string {
+	name := "qckm.sock"
+	if currentConfig().Demo {
+		name = "qckm-demo.sock"
+	}
+	if dir := os.Getenv("XDG_RUNTIME_DIR"); dir != "" {
+		return filepath.Join(dir, name)
+	}
+	return filepath.Join(StateDir(), name)
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log/slog"
+	"os"
+	"path/filepath"
+
+	"qckm/internal/i18n"
+	"qckm/internal/kimai"
+	"qckm/internal/offline"
+)
+
+// Pause stops the task and remembers it, so Resume can start it again later.
+// The stop isn't rounded, see stopTask.
+func (a *App) Pause(task kimai.Task) {
+	slog.Info("pausing task", "id", task.Id, "task", task.TextOutput())
+	client, _ := a.backend()
+	err := client.StopTask(a.ctx, task.Id)
+	if err != nil {
+		a.HandleActionError(offline.STOP, task.Id, err)
+		if !kimai.IsNetworkError(err) {
+			return
+		}
+	}
+
+	a.StopPomodoro()
+	a.setPaused(task)
+	if err == nil {
+		Notify(i18n.T("Task paused"), fmt.Sprintf("%s (%s)", task.TextOutput(), taskDuration(task)))
+	}
+	a.RequestRefresh()
+}
+
+// Resume restarts the paused task, with its description.
+func (a *App) Resume() {
+	task := a.Paused()
+	if task.Id <= 0 {
+		return
+	}
+
+	slog.Info("resuming task", "id", task.Id, "task", task.TextOutput())
+	if _, err := a.restartCopy(task); err != nil {
+		slog.Error("resuming the task failed", "id", task.Id, "err", err)
+		Notify(i18n.T("Resuming the task failed"), err.Error())
+		return
+	}
+
+	a.setPaused(kimai.Task{})
+	Notify(i18n.T("Task resumed"), task.TextOutput())
+	a.RequestRefresh()
+}
+
+// restartCopy restarts the task and gives the new timesheet the same description.
+func (a *App) restartCopy(task kimai.Task) (kimai.Task, error) {
+	ctx := a.ctx
+	client, _ := a.backend()
+	restarted, err := client.RestartTask(ctx, task.Id)
+	if err != nil {
+		return restarted, err
+	}
+	if task.Description != restarted.Description {
+		if err := client.SetDescription(ctx, restarted.Id, task.Description); err != nil {
+			slog.Error("setting the description failed", "id", restarted.Id, "err", err)
+			Notify(i18n.T("Setting the description failed"), err.Error())
+		}
+		restarted.Description = task.Description
+	}
+	return restarted, nil
+}
+
+// Paused is the task paused with Pause, a zero Task if there is none.
+func (a *App) Paused() kimai.Task {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	return a.paused
+}
+
+func (a *App) setPaused(task kimai.Task) {
+	a.mu.Lock()
+	a.paused = task
+	path := profilePath(a.profile, "paused")
+	a.mu.Unlock()
+
+	if err := savePaused(path, task); err != nil {
+		slog.Error("saving the paused task failed", "err", err)
+	}
+}
+
+// loadPaused reads the paused task saved at path, a missing file being no paused task.
+func loadPaused(path string) (kimai.Task, error) {
+	var task kimai.Task
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return task, nil
+	}
+	if err != nil {
+		return task, err
+	}
+	if err := json.Unmarshal(data, &task); err != nil {
+		return task, fmt.Errorf("corrupted paused task %s: %w", path, err)
+	}
+	return task, nil
+}
+
+func savePaused(path string, task kimai.Task) error {
+	if task.Id <= 0 {
+		err := os.Remove(path)
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+
+	data, err := json.MarshalIndent(task, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, data, 0600)
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"qckm/internal/desktop"
+	"qckm/internal/fuzzy"
+	"qckm/internal/i18n"
+	"qckm/internal/kimai"
+)
+
+// PICK_MAX_RESULTS keeps the list of matches short enough to be read.
+const PICK_MAX_RESULTS = 30
+
+// pickEntry is a project and activity pair the picker can start.
+type pickEntry struct {
+	Project  kimai.Project  `json:"project"`
+	Activity kimai.Activity `json:"activity"`
+	Label    string         `json:"label"`
+}
+
+// pickEntries lists every project and activity pair as "Customer / Project /
+// Activity", the customer being left out when unknown.
+func pickEntries(state State) []pickEntry {
+	customers := map[int]string{}
+	for _, customer := range state.Customers {
+		customers[customer.Id] = customer.Name
+	}
+
+	var entries []pickEntry
+	for _, project := range state.Projects {
+		prefix := project.Name
+		if customer, ok := customers[project.CustomerId]; ok {
+			prefix = customer + " / " + project.Name
+		}
+		for _, activity := range kimai.ActivitiesFor(state.Activities, project.Id) {
+			entries = append(entries, pickEntry{Project: project, Activity: activity, Label: prefix + " / " + activity.Name})
+		}
+	}
+	return entries
+}
+
+// searchEntries returns the entries matching query, the best first.
+func searchEntries(entries []pickEntry, query string) []pickEntry {
+	labels := make([]string, len(entries))
+	for i, entry := range entries {
+		labels[i] = entry.Label
+	}
+
+	matches := []pickEntry{}
+	for _, i := range fuzzy.Filter(query, labels) {
+		matches = append(matches, entries[i])
+	}
+	return matches
+}
+
+// Pick asks for a filter, then for one of the matching project and activity
+// pairs if there are several, and starts it.
+func (a *App) Pick() {
+	entries := pickEntries(a.State())
+	if len(entries) == 0 {
+		Notify(i18n.T("No project to pick"), i18n.T("The projects have not been fetched yet"))
+		return
+	}
+
+	query, err := desktop.Prompt("qckm", i18n.T("Search a project or an activity"), "")
+	if err != nil {
+		if !errors.Is(err, desktop.ErrCancelled) {
+			slog.Warn("search prompt failed", "err", err)
+		}
+		return
+	}
+
+	matches := searchEntries(entries, query)
+	if len(matches) == 0 {
+		Notify(i18n.T("No project or activity matches"), query)
+		return
+	}
+	if len(matches) > PICK_MAX_RESULTS {
+		matches = matches[:PICK_MAX_RESULTS]
+	}
+
+	choice := matches[0]
+	if len(matches) > 1 {
+		labels := make([]string, len(matches))
+		for i, match := range matches {
+			labels[i] = match.Label
+		}
+		i, err := desktop.Choose("qckm", i18n.T("Start a task for %q", query), labels)
+		if err != nil {
+			if !errors.Is(err, desktop.ErrCancelled) {
+				slog.Warn("search dialog failed", "err", err)
+			}
+			return
+		}
+		choice = matches[i]
+	}
+	a.Start(choice.Project, choice.Activity, kimai.StartOptions{Billable: currentConfig().Billable})
+}
+
+func searchCommand(ctx context.Context, client *kimai.Client, args []string, asJson bool) error {
+	var state State
+	var err error
+	if state.Projects, err = client.FetchProjects(ctx); err != nil {
+		return err
+	}
+	if state.Activities, err = client.FetchActivities(ctx); err != nil {
+		return err
+	}
+	// without customers the entries are still usable
+	state.Customers, _ = client.FetchCustomers(ctx)
+
+	matches := searchEntries(pickEntries(state), strings.Join(args, " "))
+	if asJson {
+		return printJson(matches)
+	}
+	for _, match := range matches {
+		fmt.Printf("%d\t%d\t%s\n", match.Project.Id, match.Activity.Id, match.Label)
+	}
+	return nil
+}
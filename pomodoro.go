@@ -0,0 +1,178 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"time"
+
+	"qckm/internal/i18n"
+	"qckm/internal/kimai"
+)
+
+const (
+	DEFAULT_POMODORO_WORK       = 25
+	DEFAULT_POMODORO_BREAK      = 5
+	DEFAULT_POMODORO_LONG_BREAK = 15
+	DEFAULT_POMODORO_CYCLES     = 4
+
+	POMODORO_WORK  = "work"
+	POMODORO_BREAK = "break"
+)
+
+// PomodoroConfig are the cycle lengths in minutes. A long break replaces
+// the short one every Cycles work periods.
+type PomodoroConfig struct {
+	Work      int `yaml:"work"`
+	Break     int `yaml:"break"`
+	LongBreak int `yaml:"long_break"`
+	Cycles    int `yaml:"cycles"`
+}
+
+func (c *PomodoroConfig) setDefaults() {
+	if c.Work <= 0 {
+		c.Work = DEFAULT_POMODORO_WORK
+	}
+	if c.Break <= 0 {
+		c.Break = DEFAULT_POMODORO_BREAK
+	}
+	if c.LongBreak <= 0 {
+		c.LongBreak = DEFAULT_POMODORO_LONG_BREAK
+	}
+	if c.Cycles <= 0 {
+		c.Cycles = DEFAULT_POMODORO_CYCLES
+	}
+}
+
+// pomodoro is a running cycle, the timesheet being stopped during breaks and
+// restarted after them.
+type pomodoro struct {
+	task  kimai.Task
+	phase string
+	cycle int
+	until time.Time
+	stop  chan struct{}
+}
+
+// StartPomodoro runs pomodoro cycles on the running task, from now on.
+func (a *App) StartPomodoro(task kimai.Task) {
+	a.StopPomodoro()
+
+	p := &pomodoro{task: task, phase: POMODORO_WORK, cycle: 1, stop: make(chan struct{})}
+	p.until = time.Now().Add(time.Duration(currentConfig().Pomodoro.Work) * time.Minute)
+	a.mu.Lock()
+	a.pomodoro = p
+	a.mu.Unlock()
+
+	slog.Info("pomodoro started", "id", task.Id, "task", task.TextOutput())
+	Notify(i18n.T("Pomodoro started"), i18n.T("%s, break at %s", task.TextOutput(), p.until.Format("15:04")))
+	go a.runPomodoro(p)
+	a.RequestRefresh()
+}
+
+func (a *App) StopPomodoro() {
+	a.mu.Lock()
+	p := a.pomodoro
+	a.pomodoro = nil
+	a.mu.Unlock()
+
+	if p != nil {
+		close(p.stop)
+		slog.Info("pomodoro stopped")
+		a.RequestRefresh()
+	}
+}
+
+// PomodoroStatus is e.g. "🍅 12 min" during work and "☕ 3 min" during
+// breaks, empty without pomodoro.
+func (a *App) PomodoroStatus() string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.pomodoro == nil {
+		return ""
+	}
+	left := int(time.Until(a.pomodoro.until).Minutes()) + 1
+	if a.pomodoro.phase == POMODORO_BREAK {
+		return fmt.Sprintf("☕ %d min", left)
+	}
+	return fmt.Sprintf("🍅 %d min", left)
+}
+
+func (a *App) runPomodoro(p *pomodoro) {
+	for {
+		a.mu.Lock()
+		wait := time.Until(p.until)
+		a.mu.Unlock()
+
+		select {
+		case <-p.stop:
+			return
+		case <-time.After(wait):
+		}
+
+		var err error
+		if p.phase == POMODORO_WORK {
+			err = a.pomodoroBreak(p)
+		} else {
+			err = a.pomodoroWork(p)
+		}
+		if err != nil {
+			slog.Error("pomodoro stopped", "err", err)
+			Notify(i18n.T("Pomodoro stopped"), err.Error())
+			a.mu.Lock()
+			if a.pomodoro == p {
+				a.pomodoro = nil
+			}
+			a.mu.Unlock()
+			return
+		}
+		a.RequestRefresh()
+	}
+}
+
+// pomodoroBreak stops the timesheet at the end of a work period.
+func (a *App) pomodoroBreak(p *pomodoro) error {
+	config := currentConfig()
+	// the user stopped or switched task in the meantime
+	if active := a.State().Active; active.Id != p.task.Id {
+		return fmt.Errorf("%s is not running anymore", p.task.TextOutput())
+	}
+	client, _ := a.backend()
+	if err := client.StopTask(a.ctx, p.task.Id); err != nil {
+		return err
+	}
+
+	length := config.Pomodoro.Break
+	if p.cycle%config.Pomodoro.Cycles == 0 {
+		length = config.Pomodoro.LongBreak
+	}
+	a.mu.Lock()
+	p.phase = POMODORO_BREAK
+	p.until = time.Now().Add(time.Duration(length) * time.Minute)
+	a.mu.Unlock()
+
+	Notify(i18n.T("Time for a break"), i18n.T("%d minutes, %s stopped after cycle %d", length, p.task.TextOutput(), p.cycle))
+	return nil
+}
+
+// pomodoroWork restarts the timesheet at the end of a break.
+func (a *App) pomodoroWork(p *pomodoro) error {
+	// the user started something else during the break
+	if active := a.State().Active; active.Id > 0 {
+		return fmt.Errorf("%s was started during the break", active.TextOutput())
+	}
+	task, err := a.restartCopy(p.task)
+	if err != nil {
+		return err
+	}
+
+	a.mu.Lock()
+	p.task = task
+	p.phase = POMODORO_WORK
+	p.cycle++
+	p.until = time.Now().Add(time.Duration(currentConfig().Pomodoro.Work) * time.Minute)
+	a.mu.Unlock()
+
+	Notify(i18n.T("Back to work"), i18n.T("%s restarted, break at %s", task.TextOutput(), p.until.Format("15:04")))
+	return nil
+}
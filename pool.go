@@ -0,0 +1,100 @@
+package main
+
+import (
+	"sync"
+
+	"github.com/heb-dtc/systray"
+)
+
+// itemPool reuses the submenu entries of a parent item across menu rebuilds.
+// systray never frees removed items, so instead of adding new entries (and
+// click handler goroutines) on every refresh, entries are retitled, and the
+// ones not needed anymore are hidden.
+type itemPool struct {
+	parent *systray.MenuItem
+	items  []*poolItem
+	used   int
+}
+
+// poolItem is a reusable entry with a single click handler goroutine for its
+// whole lifetime, running whatever action the last rebuild assigned to it.
+type poolItem struct {
+	*systray.MenuItem
+
+	mu       sync.Mutex
+	onClick  func()
+	children *itemPool
+}
+
+func newItemPool(parent *systray.MenuItem) *itemPool {
+	return &itemPool{parent: parent}
+}
+
+// Reset starts a rebuild, entries are then re-added in order with Add.
+func (p *itemPool) Reset() {
+	p.used = 0
+}
+
+// Add shows the next entry. A nil onClick makes it a disabled, informative entry.
+func (p *itemPool) Add(title string, onClick func()) *poolItem {
+	var item *poolItem
+	if p.used < len(p.items) {
+		item = p.items[p.used]
+		item.SetTitle(title)
+		item.Show()
+	} else {
+		item = &poolItem{MenuItem: p.parent.AddSubMenuItem(title, "")}
+		p.items = append(p.items, item)
+		go item.handleClicks()
+	}
+	p.used++
+
+	item.mu.Lock()
+	item.onClick = onClick
+	item.mu.Unlock()
+
+	if onClick == nil {
+		item.Disable()
+	} else {
+		item.Enable()
+	}
+	// children are rebuilt by the caller if the entry still has some
+	if item.children != nil {
+		item.children.Reset()
+		item.children.Done()
+	}
+	return item
+}
+
+// Done hides the entries that were not re-added since Reset.
+func (p *itemPool) Done() {
+	for _, item := range p.items[p.used:] {
+		item.Hide()
+	}
+}
+
+// Len is the number of entries shown since Reset.
+func (p *itemPool) Len() int {
+	return p.used
+}
+
+// Children returns the pool of the entry's own submenu.
+func (item *poolItem) Children() *itemPool {
+	if item.children == nil {
+		item.children = newItemPool(item.MenuItem)
+	}
+	item.Enable()
+	return item.children
+}
+
+func (item *poolItem) handleClicks() {
+	for range item.ClickedCh {
+		item.mu.Lock()
+		onClick := item.onClick
+		item.mu.Unlock()
+
+		if onClick != nil {
+			onClick()
+		}
+	}
+}
@@ -0,0 +1,153 @@
+package main
+
+import (
+	"log/slog"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+
+	"qckm/internal/i18n"
+)
+
+// RELOAD_DELAY groups the events of a single save, editors often writing
+// the file in several steps.
+const RELOAD_DELAY = 500 * time.Millisecond
+
+// configPath is the file config was loaded from, empty in demo mode without one.
+var configPath string
+
+// ReloadConfig reads the config file again and applies it to the running
+// tray. The profile in use is kept if it still exists, its state (history,
+// queue, favourites) only being reopened when another profile is selected.
+func (a *App) ReloadConfig() error {
+	reloaded, err := LoadConfig(configPath, "")
+	if err != nil {
+		return err
+	}
+	// the profile in use may have been removed or renamed
+	if profile := a.Profile(); profile != reloaded.SelectedProfile && slices.Contains(reloaded.ProfileNames(), profile) {
+		if reloaded, err = reloaded.UseProfile(profile); err != nil {
+			return err
+		}
+	}
+	previous := currentConfig()
+	if previous.Demo {
+		reloaded = reloaded.WithDemo(previous.URL)
+	}
+
+	setConfig(reloaded)
+	slog.Info("config reloaded", "path", configPath, "profile", reloaded.SelectedProfile)
+
+	if previous.IdleThreshold != reloaded.IdleThreshold || previous.IdleAction != reloaded.IdleAction ||
+		!reflect.DeepEqual(previous.Hotkeys, reloaded.Hotkeys) || previous.Language != reloaded.Language ||
+		!reflect.DeepEqual(previous.CustomActions, reloaded.CustomActions) ||
+		!reflect.DeepEqual(previous.ProfileNames(), reloaded.ProfileNames()) {
+		Notify(i18n.T("Config reloaded"), i18n.T("Idle detection, hotkeys, the language, the custom actions and the profile menu change after a restart"))
+	}
+
+	switch {
+	case reloaded.SelectedProfile != a.Profile():
+		a.use(reloaded)
+	case !reflect.DeepEqual(previous.connection(), reloaded.connection()):
+		a.reconnect(reloaded)
+	}
+	if previous.RefreshInterval != reloaded.RefreshInterval {
+		a.scheduleRefresh(reloaded.RefreshInterval)
+	}
+	if previous.Dashboard != reloaded.Dashboard {
+		a.ServeDashboard()
+	}
+	// the other settings apply from the next refresh and render on
+	a.RequestRefresh()
+	return nil
+}
+
+// connection are the settings of NewClient, see reconnect.
+type connection struct {
+	Profile   Profile
+	Proxy     string
+	Timeout   int
+	Retries   int
+	RateLimit float64
+}
+
+func (c Config) connection() connection {
+	profile := c.Profile
+	// rebuilt from the file names by every UseProfile
+	profile.tls = nil
+	return connection{Profile: profile, Proxy: c.Proxy, Timeout: c.Timeout, Retries: c.Retries, RateLimit: c.RateLimit}
+}
+
+// reconnect replaces the client of the profile in use, keeping its state.
+func (a *App) reconnect(profileConfig Config) {
+	slog.Info("reconnecting", "profile", profileConfig.SelectedProfile, "url", profileConfig.URL)
+	client := NewClient(profileConfig)
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.client = client
+}
+
+// scheduleRefresh (re)starts the automatic refreshes every interval seconds,
+// none if negative.
+func (a *App) scheduleRefresh(interval int) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.refreshTicker == nil {
+		a.refreshTicker = time.NewTicker(time.Hour)
+		go func(ticker *time.Ticker) {
+			for range ticker.C {
+				a.RequestRefresh()
+			}
+		}(a.refreshTicker)
+	}
+	if interval > 0 {
+		a.refreshTicker.Reset(time.Duration(interval) * time.Second)
+	} else {
+		a.refreshTicker.Stop()
+	}
+}
+
+// WatchConfig reloads the config when the file changes. The directory is
+// watched as editors replace the file rather than writing to it.
+func (a *App) WatchConfig() {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		slog.Warn("config watching disabled", "err", err)
+		return
+	}
+	if err := watcher.Add(filepath.Dir(configPath)); err != nil {
+		slog.Warn("config watching disabled", "err", err)
+		watcher.Close()
+		return
+	}
+
+	name := filepath.Clean(configPath)
+	reload := time.NewTimer(RELOAD_DELAY)
+	reload.Stop()
+	for {
+		select {
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return
+			}
+			if filepath.Clean(event.Name) == name && event.Has(fsnotify.Write|fsnotify.Create) {
+				reload.Reset(RELOAD_DELAY)
+			}
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return
+			}
+			slog.Warn("config watching failed", "err", err)
+		case <-reload.C:
+			if err := a.ReloadConfig(); err != nil {
+				slog.Error("reloading the config failed", "err", err)
+				Notify(i18n.T("Reloading the config failed"), err.Error())
+			}
+		}
+	}
+}
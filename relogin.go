@@ -0,0 +1,60 @@
+package main
+
+import (
+	"log/slog"
+
+	"github.com/zalando/go-keyring"
+
+	"qckm/internal/desktop"
+	"qckm/internal/i18n"
+	"qckm/internal/kimai"
+)
+
+// Relogin asks for a new API token after an authentication failure, checks
+// it and stores it in the keyring, then uses it without restarting.
+func (a *App) Relogin() {
+	profileConfig, err := currentConfig().UseProfile(a.Profile())
+	if err != nil {
+		slog.Error("relogin failed", "err", err)
+		return
+	}
+
+	token, err := desktop.PromptPassword("qckm", i18n.T("API token for %s", profileConfig.KeyringAccount()))
+	if err == desktop.ErrCancelled || token == "" {
+		return
+	}
+	if err != nil {
+		slog.Error("token prompt failed", "err", err)
+		Notify(i18n.T("Login failed"), i18n.T("No dialog available, run qckm login instead"))
+		return
+	}
+
+	fileToken := profileConfig.Token != "" && profileConfig.Token != keyringToken(profileConfig.KeyringAccount())
+	profileConfig.Token = token
+	client := NewClient(profileConfig)
+	if _, err := client.FetchActive(a.ctx); err != nil && !kimai.IsNoActiveTask(err) {
+		slog.Error("checking the new token failed", "err", err)
+		Notify(i18n.T("Login failed"), err.Error())
+		return
+	}
+
+	if err := keyring.Set(KEYRING_SERVICE, profileConfig.KeyringAccount(), token); err != nil {
+		slog.Warn("storing the token in the keyring failed", "err", err)
+		Notify(i18n.T("Token not stored"), i18n.T("The new token is only used until qckm quits: %s", err))
+	} else if fileToken {
+		Notify(i18n.T("Token stored in the keyring"), i18n.T("Remove the token from the config file, it takes precedence"))
+	}
+
+	slog.Info("logged in again", "profile", profileConfig.SelectedProfile)
+	a.mu.Lock()
+	a.client = client
+	a.state.AuthFailed = false
+	a.mu.Unlock()
+	a.RequestRefresh()
+}
+
+// keyringToken is the token stored in the keyring, empty if there is none.
+func keyringToken(account string) string {
+	token, _ := keyring.Get(KEYRING_SERVICE, account)
+	return token
+}
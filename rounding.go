@@ -0,0 +1,68 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"qckm/internal/kimai"
+	"qckm/internal/rounding"
+)
+
+// RoundingConfig are the increments in minutes the begin and end of a task
+// are rounded to when it is stopped, 0 to keep them.
+type RoundingConfig struct {
+	Begin int `yaml:"begin"`
+	End   int `yaml:"end"`
+	// Mode is "up" (default), "down" or "nearest", see rounding.MODES.
+	Mode string `yaml:"mode"`
+	// Minimum duration of an entry in minutes.
+	Minimum int `yaml:"minimum"`
+}
+
+func (c RoundingConfig) check() error {
+	switch c.Mode {
+	case "", rounding.UP, rounding.DOWN, rounding.NEAREST:
+	default:
+		return fmt.Errorf("invalid rounding mode %q, expected one of %v", c.Mode, rounding.MODES)
+	}
+	if c.Begin < 0 || c.End < 0 || c.Minimum < 0 {
+		return fmt.Errorf("invalid rounding, the minutes can't be negative")
+	}
+	return nil
+}
+
+func (c RoundingConfig) rules() rounding.Rules {
+	return rounding.Rules{
+		Begin:   time.Duration(c.Begin) * time.Minute,
+		End:     time.Duration(c.End) * time.Minute,
+		Mode:    c.Mode,
+		Minimum: time.Duration(c.Minimum) * time.Minute,
+	}
+}
+
+// stopTask stops the task now, with the begin and end rounded as configured.
+//
+// Pauses and pomodoro breaks stop the task with client.StopTask and don't
+// round: the same task restarts after them, rounding each part would add the
+// increment several times to what is one piece of work. Stops replayed from
+// the offline queue don't round either, the queue only keeps the task id and
+// when it was stopped, and a paused task queued while offline must not be
+// rounded on replay.
+func stopTask(ctx context.Context, client *kimai.Client, task kimai.Task) error {
+	rules := currentConfig().Rounding.rules()
+	begin := task.Begin()
+	if !rules.Enabled() || begin.IsZero() {
+		return client.StopTask(ctx, task.Id)
+	}
+
+	roundedBegin, roundedEnd := rules.Apply(begin, time.Now())
+	slog.Debug("rounding the stopped task", "id", task.Id, "begin", roundedBegin, "end", roundedEnd)
+	fields := map[string]interface{}{"end": roundedEnd.Format(kimai.DATETIME_FORMAT)}
+	if !roundedBegin.Equal(begin) {
+		fields["begin"] = roundedBegin.Format(kimai.DATETIME_FORMAT)
+	}
+	_, err := client.UpdateTask(ctx, task.Id, fields)
+	return err
+}
@@ -0,0 +1,153 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/zalando/go-keyring"
+	"golang.org/x/term"
+	"gopkg.in/yaml.v2"
+
+	"qckm/internal/desktop"
+	"qckm/internal/i18n"
+)
+
+const SETUP_ATTEMPTS = 3
+
+// RunSetup asks for the Kimai URL and credentials, on the terminal if there
+// is one or with dialogs otherwise, checks them against the server and writes
+// a config file at path.
+func RunSetup(path string) error {
+	// there is no configured language yet
+	i18n.SetLanguage(i18n.AUTO)
+	ask := askDialog
+	if term.IsTerminal(int(os.Stdin.Fd())) {
+		ask = askTerminal
+		fmt.Printf("No config found, let's create %s\n", path)
+	}
+
+	var profile Profile
+	var err error
+	for attempt := 1; attempt <= SETUP_ATTEMPTS; attempt++ {
+		if profile.URL, err = ask("Kimai URL (e.g. https://kimai.example.com)", profile.URL, false); err != nil {
+			return err
+		}
+		if profile.Username, err = ask("Username", profile.Username, false); err != nil {
+			return err
+		}
+		if profile.Token, err = ask("API token", "", true); err != nil {
+			return err
+		}
+
+		if err = checkProfile(profile); err == nil {
+			break
+		}
+		reportSetupError(err)
+	}
+	if err != nil {
+		return fmt.Errorf("setup failed: %w", err)
+	}
+
+	// keep the token out of the file when the keyring works
+	if keyring.Set(KEYRING_SERVICE, profile.KeyringAccount(), profile.Token) == nil {
+		profile.Token = ""
+	}
+
+	file := yaml.MapSlice{{Key: "url", Value: profile.URL}, {Key: "user", Value: profile.Username}}
+	if profile.Token != "" {
+		file = append(file, yaml.MapItem{Key: "token", Value: profile.Token})
+	}
+	data, err := yaml.Marshal(file)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(path, data, 0600); err != nil {
+		return err
+	}
+
+	if term.IsTerminal(int(os.Stdin.Fd())) {
+		fmt.Println(i18n.T("Config written to %s", path))
+	} else {
+		Notify(i18n.T("qckm is set up"), i18n.T("Config written to %s", path))
+	}
+	return nil
+}
+
+// checkProfile makes sure the server answers and accepts the credentials.
+func checkProfile(profile Profile) error {
+	if u, err := url.Parse(profile.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("invalid url %q, expected http(s)://host", profile.URL)
+	}
+
+	_, err := NewClient(Config{Profile: profile, Retries: -1}).FetchVersion(context.Background())
+	return err
+}
+
+func reportSetupError(err error) {
+	if term.IsTerminal(int(os.Stdin.Fd())) {
+		fmt.Fprintln(os.Stderr, "Checking the server failed:", err)
+	} else {
+		Notify(i18n.T("Checking the server failed"), err.Error())
+	}
+}
+
+func askTerminal(label string, current string, secret bool) (string, error) {
+	if current != "" {
+		fmt.Printf("%s [%s]: ", label, current)
+	} else {
+		fmt.Printf("%s: ", label)
+	}
+
+	var value string
+	if secret {
+		data, err := term.ReadPassword(int(os.Stdin.Fd()))
+		fmt.Println()
+		if err != nil {
+			return "", err
+		}
+		value = string(data)
+	} else {
+		line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		if err != nil {
+			return "", err
+		}
+		value = line
+	}
+
+	value = strings.TrimSpace(value)
+	if value == "" {
+		value = current
+	}
+	if value == "" {
+		return askTerminal(label, current, secret)
+	}
+	return value, nil
+}
+
+func askDialog(label string, current string, secret bool) (string, error) {
+	var value string
+	var err error
+	if secret {
+		value, err = desktop.PromptPassword("qckm setup", label)
+	} else {
+		value, err = desktop.Prompt("qckm setup", label, current)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return askDialog(label, current, secret)
+	}
+	return value, nil
+}
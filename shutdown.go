@@ -0,0 +1,100 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/heb-dtc/systray"
+
+	"qckm/internal/desktop"
+	"qckm/internal/i18n"
+)
+
+// SHUTDOWN_TIMEOUT bounds the requests still made while quitting, the queue
+// replay and the stop of the running task having one each.
+const SHUTDOWN_TIMEOUT = 5 * time.Second
+
+// HandleSignals quits cleanly on SIGINT and SIGTERM, as the Quit entry does.
+func (a *App) HandleSignals() {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		sig := <-signals
+		signal.Stop(signals)
+		slog.Info("quitting on signal", "signal", sig.String())
+		a.Shutdown(false)
+		systray.Quit()
+	}()
+}
+
+// Shutdown cancels the requests in flight, replays the offline queue if
+// possible and applies the configured quit_action to the running task. A
+// quit_action "ask" only warns when not interactive, e.g. on SIGTERM.
+func (a *App) Shutdown(interactive bool) {
+	a.cancel()
+	if a.control != nil {
+		a.control.Close()
+	}
+	a.mu.Lock()
+	if a.dashboard != nil {
+		a.dashboard.Close()
+	}
+	a.mu.Unlock()
+
+	client, queue := a.backend()
+	if queue != nil && queue.Len() > 0 {
+		ctx, cancel := context.WithTimeout(context.Background(), SHUTDOWN_TIMEOUT)
+		rejected, err := queue.Replay(ctx, client)
+		cancel()
+		for _, e := range rejected {
+			slog.Warn("queued action rejected", "err", e)
+		}
+		if err != nil {
+			slog.Error("saving the offline queue failed", "err", err)
+		}
+		if queued := queue.Len(); queued > 0 {
+			slog.Info("offline queue kept for the next start", "queued", queued)
+		}
+	}
+
+	active := a.State().Active
+	if active.Id <= 0 {
+		return
+	}
+
+	switch currentConfig().QuitAction {
+	case QUIT_ACTION_STOP:
+	case QUIT_ACTION_ASK:
+		if !interactive {
+			slog.Warn("quitting with a running task", "id", active.Id, "task", active.TextOutput())
+			Notify(i18n.T("qckm quit"), i18n.T("%s is still running (%s)", active.TextOutput(), taskDuration(active)))
+			return
+		}
+		text := i18n.T("%s is still running (%s).\nStop it before quitting?", active.TextOutput(), taskDuration(active))
+		stop, err := desktop.Confirm("qckm", text)
+		if err != nil {
+			slog.Warn("quit confirmation failed", "err", err)
+			return
+		}
+		if !stop {
+			return
+		}
+	default:
+		return
+	}
+
+	// started after the confirmation, the user taking their time to answer
+	ctx, cancel := context.WithTimeout(context.Background(), SHUTDOWN_TIMEOUT)
+	defer cancel()
+	if err := stopTask(ctx, client, active); err != nil {
+		slog.Error("stopping the task before quitting failed", "id", active.Id, "err", err)
+		Notify(i18n.T("Stopping the task failed"), err.Error())
+		return
+	}
+	Notify(i18n.T("Task stopped"), fmt.Sprintf("%s (%s)", active.TextOutput(), taskDuration(active)))
+}
@@ -0,0 +1,168 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"qckm/internal/ipc"
+	"qckm/internal/kimai"
+)
+
+const (
+	STATUS_FORMAT_PLAIN  = "plain"
+	STATUS_FORMAT_JSON   = "json"
+	STATUS_FORMAT_WAYBAR = "waybar"
+	// STATUS_WATCH_INTERVAL in seconds between two updates of status --watch
+	STATUS_WATCH_INTERVAL = 10
+)
+
+// statusOptions are the flags only accepted by the status command.
+type statusOptions struct {
+	format   string
+	watch    bool
+	interval int
+}
+
+// waybarStatus is the JSON a Waybar custom module with "return-type": "json" reads.
+type waybarStatus struct {
+	Text    string `json:"text"`
+	Tooltip string `json:"tooltip"`
+	Class   string `json:"class"`
+}
+
+// statusFlags declares the status flags, see flagCommands.
+func statusFlags(flags *flag.FlagSet) command {
+	var opts statusOptions
+	flags.StringVar(&opts.format, "format", STATUS_FORMAT_PLAIN, "output format: plain, json or waybar")
+	flags.BoolVar(&opts.watch, "watch", false, "print the status again every interval")
+	flags.IntVar(&opts.interval, "interval", STATUS_WATCH_INTERVAL, "seconds between two --watch updates")
+	return func(ctx context.Context, client *kimai.Client, args []string, asJson bool) error {
+		return statusCommand(ctx, client, opts, asJson)
+	}
+}
+
+func statusCommand(ctx context.Context, client *kimai.Client, opts statusOptions, asJson bool) error {
+	format := opts.format
+	if asJson {
+		format = STATUS_FORMAT_JSON
+	}
+	switch format {
+	case STATUS_FORMAT_PLAIN, STATUS_FORMAT_JSON, STATUS_FORMAT_WAYBAR:
+	default:
+		return fmt.Errorf("invalid format %q, expected %q, %q or %q", format, STATUS_FORMAT_PLAIN, STATUS_FORMAT_JSON, STATUS_FORMAT_WAYBAR)
+	}
+
+	if !opts.watch {
+		status, err := fetchStatus(ctx, client)
+		if err != nil {
+			return err
+		}
+		return printStatus(format, status, nil, false)
+	}
+
+	interval := time.Duration(opts.interval) * time.Second
+	if interval <= 0 {
+		interval = STATUS_WATCH_INTERVAL * time.Second
+	}
+	// bars read one line per update, errors are shown instead of ending the stream
+	for {
+		status, err := fetchStatus(ctx, client)
+		if err := printStatus(format, status, err, true); err != nil {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+}
+
+// fetchStatus asks the running tray, and the server when there is none or
+// it uses another profile.
+func fetchStatus(ctx context.Context, client *kimai.Client) (Status, error) {
+	var status Status
+	if err := ipc.Call(SocketPath(), ipc.STATUS, nil, &status); err == nil && status.Profile == currentConfig().SelectedProfile {
+		return status, nil
+	}
+
+	status = Status{Profile: currentConfig().SelectedProfile}
+	active, err := client.FetchActive(ctx)
+	if kimai.IsNetworkError(err) {
+		status.Offline = true
+		return status, nil
+	}
+	if err != nil && !kimai.IsNoActiveTask(err) {
+		return status, err
+	}
+	if active.Id > 0 {
+		status.Active = &active
+		status.Elapsed = int(active.Elapsed().Seconds())
+	}
+	return status, nil
+}
+
+// printStatus writes the status, or fetchErr, in the given format. Watch mode
+// prints JSON on a single line.
+func printStatus(format string, status Status, fetchErr error, watch bool) error {
+	switch format {
+	case STATUS_FORMAT_JSON:
+		var v interface{} = status.Active
+		if fetchErr != nil {
+			v = map[string]string{"error": fetchErr.Error()}
+		}
+		if watch {
+			return json.NewEncoder(os.Stdout).Encode(v)
+		}
+		return printJson(v)
+	case STATUS_FORMAT_WAYBAR:
+		return json.NewEncoder(os.Stdout).Encode(waybarStatusOf(status, fetchErr))
+	}
+
+	switch {
+	case fetchErr != nil:
+		fmt.Println("error:", fetchErr)
+	case status.Active != nil:
+		printTask(*status.Active, true)
+	case status.Offline:
+		fmt.Println("offline")
+	default:
+		fmt.Println("no active task")
+	}
+	return nil
+}
+
+func waybarStatusOf(status Status, fetchErr error) waybarStatus {
+	if fetchErr != nil {
+		return waybarStatus{Text: "qckm: error", Tooltip: fetchErr.Error(), Class: "error"}
+	}
+
+	if status.Active == nil {
+		out := waybarStatus{Text: "", Tooltip: "No active task", Class: "idle"}
+		if status.Paused != nil {
+			out = waybarStatus{Text: "⏸ " + status.Paused.TextOutput(), Tooltip: "Paused: " + status.Paused.Label(0), Class: "paused"}
+		}
+		if status.Offline {
+			out.Text = "offline"
+			out.Class = "offline"
+		}
+		return out
+	}
+
+	task := *status.Active
+	elapsed := time.Duration(status.Elapsed) * time.Second
+	out := waybarStatus{
+		Text:    fmt.Sprintf("%s / %s %s", task.Project.Name, task.Activity.Name, formatDuration(elapsed)),
+		Tooltip: fmt.Sprintf("%s (%s)", task.Label(0), formatDuration(elapsed)),
+		Class:   "running",
+	}
+	if status.Offline {
+		out.Text += " (offline)"
+		out.Class = "offline"
+	}
+	return out
+}
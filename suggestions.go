@@ -0,0 +1,213 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"qckm/internal/i18n"
+	"qckm/internal/kimai"
+	"qckm/internal/suggest"
+)
+
+const DEFAULT_SUGGESTIONS_INTERVAL = 30
+
+// SuggestionsConfig watches git branches and the focused window, suggesting
+// the project and activity of the first matching rule.
+type SuggestionsConfig struct {
+	// Interval in seconds between two checks, DEFAULT_SUGGESTIONS_INTERVAL if 0.
+	Interval int `yaml:"interval"`
+	// Repositories whose checked out branch is watched, ~/ being the home directory.
+	Repositories []string `yaml:"repositories"`
+	// Window also watches the title of the focused window.
+	Window bool             `yaml:"window"`
+	Rules  []SuggestionRule `yaml:"rules"`
+
+	rules []suggest.Rule
+}
+
+type SuggestionRule struct {
+	// Match is a regular expression on the branch or window title.
+	Match string `yaml:"match"`
+	// Source is "branch", "window" or empty for both.
+	Source      string `yaml:"source"`
+	Project     int    `yaml:"project"`
+	Activity    int    `yaml:"activity"`
+	Description string `yaml:"description"`
+}
+
+func (c *SuggestionsConfig) check() error {
+	if c.Interval < 0 {
+		return fmt.Errorf("invalid suggestions interval %d, expected seconds", c.Interval)
+	}
+	if c.Interval == 0 {
+		c.Interval = DEFAULT_SUGGESTIONS_INTERVAL
+	}
+
+	c.rules = nil
+	for i, rule := range c.Rules {
+		pattern, err := regexp.Compile(rule.Match)
+		if err != nil {
+			return fmt.Errorf("invalid match of suggestion rule %d: %w", i+1, err)
+		}
+		if rule.Source != "" && !slices.Contains(suggest.SOURCES, rule.Source) {
+			return fmt.Errorf("invalid source %q of suggestion rule %d, expected one of %v", rule.Source, i+1, suggest.SOURCES)
+		}
+		if rule.Project <= 0 || rule.Activity <= 0 {
+			return fmt.Errorf("suggestion rule %d needs a project and an activity id", i+1)
+		}
+		c.rules = append(c.rules, suggest.Rule{
+			Pattern:     pattern,
+			Source:      rule.Source,
+			ProjectId:   rule.Project,
+			ActivityId:  rule.Activity,
+			Description: rule.Description,
+		})
+	}
+	return nil
+}
+
+func (c SuggestionsConfig) enabled() bool {
+	return len(c.rules) > 0 && (len(c.Repositories) > 0 || c.Window)
+}
+
+// repositories are the watched directories, with ~/ expanded.
+func (c SuggestionsConfig) repositories() []string {
+	homeDir, _ := os.UserHomeDir()
+	dirs := make([]string, 0, len(c.Repositories))
+	for _, dir := range c.Repositories {
+		if strings.HasPrefix(dir, "~/") {
+			dir = filepath.Join(homeDir, dir[2:])
+		}
+		dirs = append(dirs, dir)
+	}
+	return dirs
+}
+
+// watched are the branches and window title seen by the last check.
+type watched struct {
+	branches map[string]string
+	window   string
+}
+
+// WatchSuggestions checks the branches and the focused window every interval,
+// doing nothing while no rule is configured so the reloaded config applies.
+func (a *App) WatchSuggestions() {
+	seen := watched{branches: map[string]string{}}
+	for {
+		if currentConfig().Suggestions.enabled() {
+			if suggestion, ok := seen.check(currentConfig().Suggestions); ok {
+				a.suggest(suggestion)
+			}
+		}
+
+		select {
+		case <-a.ctx.Done():
+			return
+		case <-time.After(time.Duration(currentConfig().Suggestions.Interval) * time.Second):
+		}
+	}
+}
+
+// check matches the branches and window title that changed since the last
+// check, the last one matching winning.
+func (w *watched) check(c SuggestionsConfig) (found suggest.Suggestion, ok bool) {
+	for _, dir := range c.repositories() {
+		branch, err := suggest.Branch(dir)
+		if err != nil {
+			slog.Debug("reading the git branch failed", "dir", dir, "err", err)
+			continue
+		}
+		if branch == w.branches[dir] {
+			continue
+		}
+		w.branches[dir] = branch
+		if suggestion, matched := suggest.Match(c.rules, suggest.BRANCH, branch); matched {
+			found, ok = suggestion, true
+		}
+	}
+
+	if c.Window {
+		title, err := suggest.WindowTitle()
+		if err != nil {
+			slog.Debug("reading the window title failed", "err", err)
+		} else if title != w.window {
+			w.window = title
+			// switching to an unrelated window keeps the suggestion
+			if suggestion, matched := suggest.Match(c.rules, suggest.WINDOW, title); matched {
+				found, ok = suggestion, true
+			}
+		}
+	}
+	return found, ok
+}
+
+// suggest replaces the suggested task, notifying it when it changes and is
+// not already running.
+func (a *App) suggest(suggestion suggest.Suggestion) {
+	a.mu.Lock()
+	previous := a.suggestion
+	a.suggestion = &suggestion
+	a.mu.Unlock()
+	if previous != nil && previous.ProjectId == suggestion.ProjectId &&
+		previous.ActivityId == suggestion.ActivityId && previous.Description == suggestion.Description {
+		return
+	}
+
+	slog.Info("task suggested", "source", suggestion.Source, "text", suggestion.Text,
+		"project", suggestion.ProjectId, "activity", suggestion.ActivityId)
+	if project, activity, ok := a.Suggested(); ok && !isRunning(a.State(), project, activity) {
+		Notify(i18n.T("Suggested task"), i18n.T("[%s] %s, from %s %q", project.Name, activity.Name, i18n.T(suggestion.Source), suggestion.Text))
+	}
+	a.RequestRefresh()
+}
+
+// Suggested is the project and activity of the last suggestion, ok being
+// false without one or when they are not in the fetched projects.
+func (a *App) Suggested() (project kimai.Project, activity kimai.Activity, ok bool) {
+	a.mu.Lock()
+	suggestion := a.suggestion
+	state := a.state
+	a.mu.Unlock()
+	if suggestion == nil {
+		return project, activity, false
+	}
+
+	for _, p := range state.Projects {
+		if p.Id == suggestion.ProjectId {
+			project, ok = p, true
+		}
+	}
+	for _, candidate := range kimai.ActivitiesFor(state.Activities, suggestion.ProjectId) {
+		if candidate.Id == suggestion.ActivityId {
+			return project, candidate, ok
+		}
+	}
+	return project, activity, false
+}
+
+// StartSuggested starts the suggested task, with the description of the rule.
+func (a *App) StartSuggested() {
+	project, activity, ok := a.Suggested()
+	if !ok {
+		return
+	}
+	a.mu.Lock()
+	description := a.suggestion.Description
+	a.mu.Unlock()
+	a.Start(project, activity, kimai.StartOptions{Description: description, Billable: currentConfig().Billable})
+}
+
+func isRunning(state State, project kimai.Project, activity kimai.Activity) bool {
+	for _, task := range state.Running {
+		if task.Project.Id == project.Id && task.Activity.Id == activity.Id {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"log/slog"
+	"strings"
+	"time"
+
+	"qckm/internal/i18n"
+	"qckm/internal/stats"
+)
+
+// SUMMARY_MIN_GAP is the least untracked time the daily summary mentions.
+const SUMMARY_MIN_GAP = 15 * time.Minute
+
+// dailySummaryAt is today's daily_summary time, the zero time if not set.
+func dailySummaryAt(now time.Time) time.Time {
+	if currentConfig().DailySummary == "" {
+		return time.Time{}
+	}
+	clock, _ := time.Parse("15:04", currentConfig().DailySummary)
+	return time.Date(now.Year(), now.Month(), now.Day(), clock.Hour(), clock.Minute(), 0, 0, now.Location())
+}
+
+// CheckDailySummary sends the daily summary when the daily_summary time
+// passed since the previous check, so not when starting after it.
+func (a *App) CheckDailySummary(now time.Time) {
+	a.mu.Lock()
+	previous := a.summaryChecked
+	a.summaryChecked = now
+	a.mu.Unlock()
+
+	at := dailySummaryAt(now)
+	if at.IsZero() || previous.IsZero() || now.Before(at) || !previous.Before(at) {
+		return
+	}
+	a.SendDailySummary()
+}
+
+// SendDailySummary notifies today's total per project, the running tasks and
+// the untracked time between today's tasks, fetched again for fresh totals.
+func (a *App) SendDailySummary() {
+	client, _ := a.backend()
+	now := time.Now()
+	today := stats.StartOfDay(now)
+	tasks, err := client.FetchTimesheets(a.ctx, today, today.AddDate(0, 0, 1))
+	if err != nil {
+		slog.Error("fetching today's timesheets for the summary failed", "err", err)
+		Notify(i18n.T("Daily summary unavailable"), err.Error())
+		return
+	}
+
+	summary := stats.Summarize(tasks, today, now.Add(time.Second))
+	var lines []string
+	for _, project := range summary.PerProject {
+		lines = append(lines, project.Project+" — "+formatDuration(project.Total))
+	}
+	if len(lines) == 0 {
+		lines = append(lines, i18n.T("Nothing tracked today"))
+	}
+	for _, task := range tasks {
+		if task.Running() {
+			lines = append(lines, i18n.T("%s still running (%s)", task.TextOutput(), taskDuration(task)))
+		}
+	}
+	if untracked := stats.Untracked(tasks, today, now); untracked >= SUMMARY_MIN_GAP {
+		lines = append(lines, i18n.T("%s untracked between the tasks", formatDuration(untracked)))
+	}
+
+	slog.Info("daily summary", "total", summary.Total, "tasks", len(tasks))
+	Notify(i18n.T("Today: %s", formatDuration(summary.Total)), strings.Join(lines, "\n"))
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"time"
+
+	"qckm/internal/stats"
+)
+
+const (
+	TARGET_REACHED = "✅"
+	TARGET_BEHIND  = "⚠️"
+)
+
+// weeklyTarget is the configured weekly_target, 0 if disabled.
+func weeklyTarget() time.Duration {
+	return time.Duration(currentConfig().WeeklyTarget * float64(time.Hour))
+}
+
+// weekProgress renders the time tracked this week against weekly_target, e.g.
+// "27:30 h / 38:00 h", with a hint once reached or when behind the days
+// already past. It's empty without target.
+func weekProgress(state State, now time.Time) string {
+	target := weeklyTarget()
+	if target <= 0 {
+		return ""
+	}
+
+	total := stats.Summarize(state.Week, stats.StartOfWeek(now), now.Add(time.Second)).Total
+	text := formatDuration(total) + " / " + formatDuration(target)
+	switch {
+	case total >= target:
+		text += " " + TARGET_REACHED
+	case total < stats.Expected(target, now):
+		text += " " + TARGET_BEHIND
+	}
+	return text
+}
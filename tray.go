@@ -0,0 +1,674 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/heb-dtc/systray"
+
+	"qckm/internal/autostart"
+	"qckm/internal/export"
+	"qckm/internal/i18n"
+	"qckm/internal/kimai"
+	"qckm/internal/stats"
+)
+
+// MAX_LABEL_LENGTH keeps entries with long descriptions from widening the whole menu.
+const MAX_LABEL_LENGTH = 60
+
+// Menu is the tray menu. Only Render touches the entry pools, and it is only
+// called from the App refresh loop.
+type Menu struct {
+	app *App
+
+	syncItem       *systray.MenuItem
+	errorItem      *systray.MenuItem
+	offlineItem    *systray.MenuItem
+	authItem       *systray.MenuItem
+	suggestItem    *systray.MenuItem
+	favouritesMenu *systray.MenuItem
+	recentMenu     *systray.MenuItem
+	startMenu      *systray.MenuItem
+	activeMenu     *systray.MenuItem
+	todayMenu      *systray.MenuItem
+	weekMenu       *systray.MenuItem
+	teamMenu       *systray.MenuItem
+	webMenu        *systray.MenuItem
+	copyMenu       *systray.MenuItem
+	dashboardItem  *systray.MenuItem
+	profiles       map[string]*systray.MenuItem
+	// icon is the variant shown, see updateIcon. UpdateStatus also runs
+	// outside the refresh loop, hence the lock.
+	iconMu sync.Mutex
+	icon   string
+
+	favourites *itemPool
+	recent     *itemPool
+	start      *itemPool
+	active     *itemPool
+	today      *itemPool
+	week       *itemPool
+	team       *itemPool
+	web        *itemPool
+	copy       *itemPool
+}
+
+// onReady builds the menu of app, whose control socket already listens, and
+// starts the background loops.
+func onReady(app *App) {
+	config := currentConfig()
+	menu := NewMenu(app)
+	app.mu.Lock()
+	app.menu = menu
+	app.mu.Unlock()
+
+	app.scheduleRefresh(config.RefreshInterval)
+	if configPath != "" {
+		go app.WatchConfig()
+	}
+
+	if config.IdleThreshold > 0 {
+		go app.WatchIdle()
+	}
+	if len(config.Hotkeys) > 0 {
+		app.RegisterHotkeys()
+	}
+	app.HandleSignals()
+	go app.WatchSuggestions()
+	app.ServeDashboard()
+
+	app.CheckDailySummary(time.Now())
+	go func() {
+		ticker := time.NewTicker(time.Minute)
+		for range ticker.C {
+			menu.UpdateStatus(app.State(), app.Queued())
+			app.CheckAlerts()
+			app.CheckDailySummary(time.Now())
+		}
+	}()
+
+	app.RequestRefresh()
+	go app.Run()
+}
+
+func onExit() {
+}
+
+func NewMenu(app *App) *Menu {
+	m := &Menu{app: app, icon: ICON_IDLE}
+	setIcon(m.icon)
+
+	m.syncItem = systray.AddMenuItem(i18n.T("Syncing…"), "")
+	m.syncItem.Disable()
+	m.errorItem = systray.AddMenuItem("", "")
+	m.errorItem.Disable()
+	m.errorItem.Hide()
+	m.offlineItem = systray.AddMenuItem(i18n.T("Offline"), i18n.T("The Kimai server can't be reached"))
+	m.offlineItem.Disable()
+	m.offlineItem.Hide()
+	m.authItem = systray.AddMenuItem(i18n.T("Authentication failed — log in again…"), i18n.T("Enter a new API token"))
+	m.authItem.Hide()
+	m.suggestItem = systray.AddMenuItem(i18n.T("Start suggested"), i18n.T("Start the task matching the git branch or window"))
+	m.suggestItem.Hide()
+	m.favouritesMenu = systray.AddMenuItem(i18n.T("Favourites"), i18n.T("Start a pinned task"))
+	m.recentMenu = systray.AddMenuItem(i18n.T("Recent"), "")
+	m.startMenu = systray.AddMenuItem(i18n.T("Start new…"), i18n.T("Start a new task"))
+	searchAction := systray.AddMenuItem(i18n.T("Search all projects…"), i18n.T("Start a task on any project, filtered by name"))
+	systray.AddSeparator()
+	m.activeMenu = systray.AddMenuItem(i18n.T("Active"), "")
+	systray.AddSeparator()
+	m.todayMenu = systray.AddMenuItem(i18n.T("Today"), i18n.T("Time tracked today"))
+	m.weekMenu = systray.AddMenuItem(i18n.T("This week"), i18n.T("Time tracked this week"))
+	m.teamMenu = systray.AddMenuItem(i18n.T("Team"), i18n.T("Running timers of all the users"))
+	m.teamMenu.Hide()
+	systray.AddSeparator()
+	refreshAction := systray.AddMenuItem(i18n.T("Refresh"), i18n.T("Refresh the menu"))
+	reloadAction := systray.AddMenuItem(i18n.T("Reload config"), i18n.T("Read the config file again"))
+	if configPath == "" {
+		reloadAction.Hide()
+	}
+	m.webMenu = systray.AddMenuItem(i18n.T("Open in Kimai"), i18n.T("Open the Kimai web interface"))
+	m.copyMenu = systray.AddMenuItem(i18n.T("Copy"), i18n.T("Copy a task summary, timesheet ID or link"))
+	m.dashboardItem = systray.AddMenuItem(i18n.T("Open dashboard"), i18n.T("Show the timers in the browser"))
+	m.dashboardItem.Hide()
+	m.addExportMenu()
+	m.addActionsMenu()
+	m.addProfileMenu()
+	autostartEnabled, err := autostart.Enabled()
+	if err != nil {
+		slog.Warn("autostart state unknown", "err", err)
+	}
+	autostartAction := systray.AddMenuItemCheckbox(i18n.T("Autostart at login"), i18n.T("Start qckm when you log in"), autostartEnabled)
+	systray.AddSeparator()
+	quitAction := systray.AddMenuItem(i18n.T("Quit"), i18n.T("Quit the whole app"))
+
+	m.favourites = newItemPool(m.favouritesMenu)
+	m.recent = newItemPool(m.recentMenu)
+	m.start = newItemPool(m.startMenu)
+	m.active = newItemPool(m.activeMenu)
+	m.today = newItemPool(m.todayMenu)
+	m.week = newItemPool(m.weekMenu)
+	m.team = newItemPool(m.teamMenu)
+	m.web = newItemPool(m.webMenu)
+	m.copy = newItemPool(m.copyMenu)
+
+	go func() {
+		for range m.authItem.ClickedCh {
+			app.Relogin()
+		}
+	}()
+
+	go func() {
+		for range m.suggestItem.ClickedCh {
+			app.StartSuggested()
+		}
+	}()
+
+	go func() {
+		for range searchAction.ClickedCh {
+			app.Pick()
+		}
+	}()
+
+	go func() {
+		for range m.dashboardItem.ClickedCh {
+			app.OpenDashboard()
+		}
+	}()
+
+	go func() {
+		for range refreshAction.ClickedCh {
+			app.RequestRefresh()
+		}
+	}()
+
+	go func() {
+		for range reloadAction.ClickedCh {
+			if err := app.ReloadConfig(); err != nil {
+				slog.Error("reloading the config failed", "err", err)
+				Notify(i18n.T("Reloading the config failed"), err.Error())
+				continue
+			}
+			Notify(i18n.T("Config reloaded"), configPath)
+		}
+	}()
+
+	go func() {
+		for range autostartAction.ClickedCh {
+			enabled, err := ToggleAutostart()
+			if err != nil {
+				slog.Error("changing the autostart failed", "err", err)
+				Notify(i18n.T("Changing the autostart failed"), err.Error())
+				continue
+			}
+			slog.Info("autostart changed", "enabled", enabled)
+			if enabled {
+				autostartAction.Check()
+			} else {
+				autostartAction.Uncheck()
+			}
+		}
+	}()
+
+	go func() {
+		<-quitAction.ClickedCh
+		app.Shutdown(true)
+		systray.Quit()
+	}()
+
+	return m
+}
+
+// addActionsMenu lists the custom actions, if any.
+func (m *Menu) addActionsMenu() {
+	if len(currentConfig().CustomActions) == 0 {
+		return
+	}
+	actionsMenu := systray.AddMenuItem(i18n.T("Actions"), i18n.T("Run the commands of custom_actions"))
+	for _, action := range currentConfig().CustomActions {
+		action := action
+		item := actionsMenu.AddSubMenuItem(action.Title, action.Command)
+		go func() {
+			for range item.ClickedCh {
+				m.app.RunAction(action)
+			}
+		}()
+	}
+}
+
+// addExportMenu saves or copies the timesheets of a range, see App.Export.
+func (m *Menu) addExportMenu() {
+	exportMenu := systray.AddMenuItem(i18n.T("Export…"), i18n.T("Export timesheets as %s", currentConfig().ExportFormat))
+	titles := map[string]string{
+		export.TODAY:      i18n.T("Today"),
+		export.THIS_WEEK:  i18n.T("This week"),
+		export.LAST_WEEK:  i18n.T("Last week"),
+		export.THIS_MONTH: i18n.T("This month"),
+	}
+	for _, name := range export.RANGES {
+		name := name
+		rangeMenu := exportMenu.AddSubMenuItem(titles[name], "")
+		save := rangeMenu.AddSubMenuItem(i18n.T("Save to %s", exportDir()), "")
+		copyItem := rangeMenu.AddSubMenuItem(i18n.T("Copy to clipboard"), "")
+		calendar := rangeMenu.AddSubMenuItem(i18n.T("Save as calendar (.ics)"), i18n.T("One event per timesheet, to import in a calendar app"))
+		if currentConfig().ExportFormat == export.ICS {
+			calendar.Hide()
+		}
+		go func() {
+			for {
+				select {
+				case <-save.ClickedCh:
+					m.app.Export(name, currentConfig().ExportFormat, false)
+				case <-copyItem.ClickedCh:
+					m.app.Export(name, currentConfig().ExportFormat, true)
+				case <-calendar.ClickedCh:
+					m.app.Export(name, export.ICS, false)
+				}
+			}
+		}()
+	}
+}
+
+// addProfileMenu lets the user switch between the config profiles, if there is more than one.
+func (m *Menu) addProfileMenu() {
+	names := currentConfig().ProfileNames()
+	if len(names) < 2 {
+		return
+	}
+
+	current := m.app.Profile()
+	profileMenu := systray.AddMenuItem(i18n.T("Switch profile"), i18n.T("Use another Kimai instance"))
+	m.profiles = map[string]*systray.MenuItem{}
+	for _, name := range names {
+		item := profileMenu.AddSubMenuItemCheckbox(name, "", name == current)
+		m.profiles[name] = item
+		go func(name string) {
+			for range item.ClickedCh {
+				if err := m.app.SwitchProfile(name); err != nil {
+					slog.Error("switching profile failed", "profile", name, "err", err)
+					Notify(i18n.T("Switching profile failed"), err.Error())
+					continue
+				}
+				for other, otherItem := range m.profiles {
+					if other == name {
+						otherItem.Check()
+					} else {
+						otherItem.Uncheck()
+					}
+				}
+			}
+		}(name)
+	}
+}
+
+func (m *Menu) Render(state State) {
+	config := currentConfig()
+	if project, activity, ok := m.app.Suggested(); ok && !isRunning(state, project, activity) {
+		m.suggestItem.SetTitle(i18n.T("Start suggested: [%s] %s", project.Name, activity.Name))
+		m.suggestItem.Show()
+	} else {
+		m.suggestItem.Hide()
+	}
+	if config.Dashboard.Port > 0 {
+		m.dashboardItem.Show()
+	} else {
+		m.dashboardItem.Hide()
+	}
+
+	m.favourites.Reset()
+	for _, favourite := range m.app.Favourites() {
+		favourite := favourite
+		m.favourites.Add(kimai.Truncate(favourite.TextOutput(), MAX_LABEL_LENGTH), func() {
+			m.app.Start(favourite.Project, favourite.Activity, favouriteOptions(favourite))
+		})
+	}
+	m.favourites.Done()
+	if m.favourites.Len() == 0 {
+		m.favouritesMenu.Disable()
+	} else {
+		m.favouritesMenu.Enable()
+	}
+
+	m.recent.Reset()
+	if config.RecentLayout == RECENT_LAYOUT_GROUPED {
+		m.renderGroupedRecent(state)
+	} else {
+		for _, task := range state.Recent {
+			task := task
+			m.recent.Add(task.Label(MAX_LABEL_LENGTH), func() { m.app.Restart(task) })
+		}
+	}
+	m.recent.Done()
+
+	m.active.Reset()
+	paused := m.app.Paused()
+	pomodoro := m.app.PomodoroStatus()
+	if state.Active.Id <= 0 && paused.Id <= 0 && pomodoro == "" {
+		m.activeMenu.Disable()
+	} else {
+		m.activeMenu.Enable()
+	}
+	if paused.Id > 0 {
+		m.active.Add(i18n.T("Resume %s", paused.Label(MAX_LABEL_LENGTH)), func() { m.app.Resume() })
+	}
+	switch {
+	case len(state.Running) > 1:
+		// one submenu per running task
+		for _, task := range state.Running {
+			task := task
+			actions := m.active.Add(fmt.Sprintf("%s (%s)", task.Label(MAX_LABEL_LENGTH), taskDuration(task)), nil).Children()
+			m.addTaskActions(actions, task, pomodoro)
+			actions.Done()
+		}
+		running := state.Running
+		m.active.Add(i18n.T("Stop all (%d)", len(running)), func() { m.app.StopAll(running) })
+	case state.Active.Id > 0:
+		task := state.Active
+		m.active.Add(fmt.Sprintf("%s (%s)", task.Label(MAX_LABEL_LENGTH), taskDuration(task)), nil)
+		m.addTaskActions(m.active, task, pomodoro)
+	}
+	if pomodoro != "" {
+		m.active.Add(i18n.T("Stop pomodoro (%s)", pomodoro), func() { m.app.StopPomodoro() })
+	}
+	m.active.Done()
+
+	m.start.Reset()
+	for _, project := range state.Projects {
+		project := project
+		activities := m.start.Add(project.Name, nil).Children()
+		for _, activity := range kimai.ActivitiesFor(state.Activities, project.Id) {
+			activity := activity
+			activities.Add(activity.Name, func() { m.app.Start(project, activity, kimai.StartOptions{Billable: config.Billable}) })
+		}
+		activities.Done()
+	}
+	m.start.Done()
+	if m.start.Len() == 0 {
+		m.startMenu.Disable()
+	} else {
+		m.startMenu.Enable()
+	}
+
+	m.web.Reset()
+	m.web.Add(i18n.T("Timesheets"), func() { m.app.OpenWeb(0) })
+	if state.Active.Id > 0 {
+		task := state.Active
+		m.web.Add(i18n.T("Active: %s", task.Label(MAX_LABEL_LENGTH)), func() { m.app.OpenWeb(task.Id) })
+	}
+	for _, task := range state.Recent {
+		task := task
+		m.web.Add(task.Label(MAX_LABEL_LENGTH), func() { m.app.OpenWeb(task.Id) })
+	}
+	m.web.Done()
+
+	m.copy.Reset()
+	if state.Active.Id > 0 {
+		task := state.Active
+		m.copy.Add(i18n.T("Summary of the active task"), func() { m.app.CopySummary(task) })
+	}
+	for _, task := range state.Recent {
+		task := task
+		items := m.copy.Add(task.Label(MAX_LABEL_LENGTH), nil).Children()
+		m.addCopyActions(items, task)
+		items.Done()
+	}
+	m.copy.Done()
+	if m.copy.Len() == 0 {
+		m.copyMenu.Disable()
+	} else {
+		m.copyMenu.Enable()
+	}
+
+	m.renderTotals(state)
+	m.renderTeam(state)
+	m.UpdateStatus(state, m.app.Queued())
+}
+
+// addTaskActions adds the entries acting on a running task to items.
+func (m *Menu) addTaskActions(items *itemPool, task kimai.Task, pomodoro string) {
+	for _, field := range task.MetaFields {
+		if field.Value != "" {
+			items.Add(field.Name+": "+field.Value, nil)
+		}
+	}
+	items.Add(i18n.T("Edit description…"), func() { m.app.EditDescription(task) })
+	items.Add(i18n.T("Open in Kimai"), func() { m.app.OpenWeb(task.Id) })
+	copyItems := items.Add(i18n.T("Copy"), nil).Children()
+	copyItems.Add(i18n.T("Summary"), func() { m.app.CopySummary(task) })
+	m.addCopyActions(copyItems, task)
+	copyItems.Done()
+	if m.app.IsFavourite(task) {
+		items.Add(i18n.T("Unpin from favourites"), func() { m.app.ToggleFavourite(task) })
+	} else {
+		items.Add(i18n.T("Pin to favourites"), func() { m.app.ToggleFavourite(task) })
+	}
+	if pomodoro == "" {
+		items.Add(i18n.T("Start pomodoro"), func() { m.app.StartPomodoro(task) })
+	}
+	items.Add(i18n.T("Pause"), func() { m.app.Pause(task) })
+	startItems := items.Add(i18n.T("Start at…"), nil).Children()
+	stopItems := items.Add(i18n.T("Stop at…"), nil).Children()
+	for _, choice := range TimeChoices() {
+		choice := choice
+		startItems.Add(choice.Label, func() { m.app.StartAt(task, choice.At(time.Now())) })
+		stopItems.Add(choice.Label, func() { m.app.StopAt(task, choice.At(time.Now())) })
+	}
+	startItems.Add(i18n.T("Other time…"), func() { m.app.StartAtTyped(task) })
+	startItems.Done()
+	stopItems.Add(i18n.T("Other time…"), func() { m.app.StopAtTyped(task) })
+	stopItems.Done()
+	items.Add(i18n.T("Stop"), func() { m.app.Stop(task) })
+}
+
+// addCopyActions copies the timesheet id or link of task.
+func (m *Menu) addCopyActions(items *itemPool, task kimai.Task) {
+	items.Add(i18n.T("Timesheet ID (%d)", task.Id), func() { m.app.CopyId(task) })
+	items.Add(i18n.T("Link"), func() { m.app.CopyLink(task) })
+}
+
+// renderGroupedRecent nests the recent tasks in customer then project
+// submenus, in the order they were last used.
+func (m *Menu) renderGroupedRecent(state State) {
+	customerNames := map[int]string{}
+	for _, customer := range state.Customers {
+		customerNames[customer.Id] = customer.Name
+	}
+
+	var customers []int
+	projects := map[int][]kimai.Project{}
+	tasks := map[int][]kimai.Task{}
+	for _, task := range state.Recent {
+		customerId := task.Project.CustomerId
+		if _, ok := projects[customerId]; !ok {
+			customers = append(customers, customerId)
+		}
+		if len(tasks[task.Project.Id]) == 0 {
+			projects[customerId] = append(projects[customerId], task.Project)
+		}
+		tasks[task.Project.Id] = append(tasks[task.Project.Id], task)
+	}
+
+	for _, customerId := range customers {
+		name, ok := customerNames[customerId]
+		if !ok {
+			name = i18n.T("Other")
+		}
+		customerItems := m.recent.Add(name, nil).Children()
+		for _, project := range projects[customerId] {
+			projectItems := customerItems.Add(project.Name, nil).Children()
+			for _, task := range tasks[project.Id] {
+				task := task
+				// the project is already the submenu title
+				label := strings.TrimPrefix(task.Label(MAX_LABEL_LENGTH), "["+task.Project.Name+"] ")
+				projectItems.Add(label, func() { m.app.Restart(task) })
+			}
+			projectItems.Done()
+		}
+		customerItems.Done()
+	}
+}
+
+// renderTotals fills the Today and This week menus with the tracked time per project (and per day).
+func (m *Menu) renderTotals(state State) {
+	now := time.Now()
+	today := stats.Summarize(state.Week, stats.StartOfDay(now), now.Add(time.Second))
+	week := stats.Summarize(state.Week, stats.StartOfWeek(now), now.Add(time.Second))
+
+	m.todayMenu.SetTitle(i18n.T("Today: %s", formatDuration(today.Total)))
+	m.today.Reset()
+	for _, project := range today.PerProject {
+		m.today.Add(fmt.Sprintf("%s — %s", project.Project, formatDuration(project.Total)), nil)
+	}
+	m.today.Done()
+
+	if progress := weekProgress(state, now); progress != "" {
+		m.weekMenu.SetTitle(i18n.T("This week: %s", progress))
+	} else {
+		m.weekMenu.SetTitle(i18n.T("This week: %s", formatDuration(week.Total)))
+	}
+	m.week.Reset()
+	for _, day := range week.PerDay {
+		m.week.Add(fmt.Sprintf("%s — %s", i18n.T(day.Day.Format("Monday")), formatDuration(day.Total)), nil)
+	}
+	if len(week.PerDay) > 0 && len(week.PerProject) > 0 {
+		m.week.Add("────────", nil)
+	}
+	for _, project := range week.PerProject {
+		m.week.Add(fmt.Sprintf("%s — %s", project.Project, formatDuration(project.Total)), nil)
+	}
+	m.week.Done()
+}
+
+// renderTeam lists who is tracking what, the menu being hidden without config.Team
+// or the permission to see the other users' timesheets.
+func (m *Menu) renderTeam(state State) {
+	if state.Team == nil {
+		m.teamMenu.Hide()
+		return
+	}
+	m.teamMenu.Show()
+	m.teamMenu.SetTitle(i18n.T("Team (%d)", len(state.Team)))
+
+	m.team.Reset()
+	for _, task := range state.Team {
+		m.team.Add(fmt.Sprintf("%s — %s (%s)", task.User.Name(), task.Label(MAX_LABEL_LENGTH), taskDuration(task.Task)), nil)
+	}
+	if len(state.Team) == 0 {
+		m.team.Add(i18n.T("Nobody is tracking time"), nil)
+	}
+	m.team.Done()
+}
+
+// UpdateStatus refreshes the sync and offline entries, the icon and the title.
+func (m *Menu) UpdateStatus(state State, queued int) {
+	switch {
+	case state.Synced.IsZero():
+		m.syncItem.SetTitle(i18n.T("Syncing…"))
+		m.errorItem.Hide()
+	case state.Failure == nil:
+		m.syncItem.SetTitle(i18n.T("Last sync %s — OK", state.Synced.Format("15:04")))
+		m.errorItem.Hide()
+	default:
+		m.syncItem.SetTitle(i18n.T("Sync failed: %s", failureReason(state.Failure)))
+		m.errorItem.SetTitle(kimai.Truncate(state.Failure.Error(), 2*MAX_LABEL_LENGTH))
+		m.errorItem.SetTooltip(state.Failure.Error())
+		m.errorItem.Show()
+	}
+
+	switch {
+	case state.Offline && queued > 0:
+		m.offlineItem.SetTitle(i18n.T("Offline — %d queued action(s)", queued))
+		m.offlineItem.Show()
+	case state.Offline:
+		m.offlineItem.SetTitle(i18n.T("Offline"))
+		m.offlineItem.Show()
+	case state.Unsupported:
+		m.offlineItem.SetTitle(i18n.T("Kimai version not supported"))
+		m.offlineItem.Show()
+	case state.Stale && !state.Updated.IsZero():
+		updated := state.Updated.Format("15:04")
+		// the local history may be older than today
+		if state.Updated.Before(stats.StartOfDay(time.Now())) {
+			updated = state.Updated.Format("2006-01-02 15:04")
+		}
+		m.offlineItem.SetTitle(i18n.T("Showing data from %s", updated))
+		m.offlineItem.Show()
+	default:
+		m.offlineItem.Hide()
+	}
+	if state.AuthFailed {
+		m.authItem.Show()
+	} else {
+		m.authItem.Hide()
+	}
+
+	m.updateIcon(state)
+	m.UpdateTitle(state)
+}
+
+// updateIcon shows the icon variant of the state when it changed.
+func (m *Menu) updateIcon(state State) {
+	variant := iconState(state)
+
+	m.iconMu.Lock()
+	defer m.iconMu.Unlock()
+	if variant != m.icon {
+		m.icon = variant
+		setIcon(variant)
+	}
+}
+
+// failureReason is the short reason of a failed refresh, e.g. the HTTP
+// status "502 Bad Gateway".
+func failureReason(err error) string {
+	var apiErr *kimai.APIError
+	switch {
+	case kimai.IsNetworkError(err):
+		return i18n.T("Kimai unreachable")
+	case kimai.IsAuthError(err):
+		return i18n.T("Authentication failed")
+	case kimai.IsUnsupportedVersion(err):
+		return i18n.T("Kimai version not supported")
+	case errors.As(err, &apiErr):
+		return apiErr.Status
+	}
+	return err.Error()
+}
+
+// UpdateTitle shows the running task and its elapsed time next to the tray icon.
+// The title is only rendered on Linux and macOS, the tooltip on macOS and Windows.
+func (m *Menu) UpdateTitle(state State) {
+	var title, tooltip string
+	task := state.Active
+	switch {
+	case task.Id > 0:
+		title = fmt.Sprintf("%s / %s — %s", task.Project.Name, task.Activity.Name, formatDuration(task.Elapsed()))
+		if others := len(state.Running) - 1; others > 0 {
+			title += fmt.Sprintf(" (+%d)", others)
+		}
+		if state.Offline {
+			title += " " + i18n.T("(offline)")
+		}
+		tooltip = title
+	case state.Offline:
+		title, tooltip = i18n.T("offline"), "qckm "+i18n.T("(offline)")
+	default:
+		tooltip = "qckm"
+	}
+
+	if pomodoro := m.app.PomodoroStatus(); pomodoro != "" {
+		title = strings.TrimSpace(pomodoro + " " + title)
+		tooltip = pomodoro + " — " + tooltip
+	}
+	if currentConfig().WeeklyTargetTooltip {
+		if progress := weekProgress(state, time.Now()); progress != "" {
+			tooltip += "\n" + i18n.T("This week: %s", progress)
+		}
+	}
+	systray.SetTitle(title)
+	systray.SetTooltip(tooltip)
+}